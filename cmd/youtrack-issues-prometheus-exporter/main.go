@@ -4,50 +4,310 @@ import (
 	"fmt"
 	"github.com/alecthomas/kingpin"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/filesource"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/httpwrap"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/monitoring"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/prometheus"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/youtrack"
+	pr "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"os"
 	"time"
 )
 
-var configPath = kingpin.Flag("config", "Path to config file").Default("config/config.json").Short('c').String()
+// Exit codes.
+const (
+	exitOK = iota
+	// exitUsage is returned for invalid command-line flags
+	exitUsage
+	// exitConfigRead is returned if config file can not be read
+	exitConfigRead
+	// exitConfig is returned for invalid config
+	exitConfig
+	// exitSetup is returned if HTTP transport or YouTrack client can not be created
+	exitSetup
+	// exitListen is returned if metrics listener fails
+	exitListen
+	// exitQuery is returned if query command fails
+	exitQuery
+	// exitRefresh is returned if some refreshes failed in one-shot mode
+	exitRefresh
+	// exitTextfile is returned if textfile can not be written in one-shot mode
+	exitTextfile
+)
+
+// Commands.
+const (
+	commandRun         = "run"
+	commandCheckConfig = "check-config"
+	commandQuery       = "query"
+)
+
+// Query command output formats.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type options struct {
+	configPath string
+	logLevel   string
+	logFormat  string
+	// query is query name or search query of query command
+	query  string
+	output string
+	// once enables one-shot mode which writes metrics to textfilePath instead of serving them
+	once         bool
+	textfilePath string
+}
 
 func main() {
-	_ = kingpin.Parse()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs exporter with passed command-line arguments and returns exit code.
+// Usage is written to stdout, errors and logs are written to stderr.
+func run(args []string, stdout, stderr io.Writer) int {
+	var (
+		opts       options
+		terminated bool
+	)
+
+	app := kingpin.New("youtrack-issues-prometheus-exporter", "Exports YouTrack issues to Prometheus for any search queries")
+	app.UsageWriter(stdout).ErrorWriter(stderr)
+	// help is written by kingpin, run returns instead of exit
+	app.Terminate(func(int) { terminated = true })
+
+	app.Flag("config", "Path to config file").Default("config/config.json").Short('c').StringVar(&opts.configPath)
+	app.Flag("log.level", "Log level: debug, info, warn or error").Default("info").EnumVar(&opts.logLevel, "debug", "info", "warn", "error")
+	app.Flag("log.format", "Log format: logfmt or json").Default(logging.FormatLogfmt).EnumVar(&opts.logFormat, logging.FormatLogfmt, logging.FormatJSON)
+
+	app.Flag("once", "Refresh metrics once, write them to textfile and exit").BoolVar(&opts.once)
+	app.Flag("textfile.path", "Path to textfile written in one-shot mode").Default("youtrack_issues.prom").StringVar(&opts.textfilePath)
+
+	app.Command(commandRun, "Run exporter").Default()
+	app.Command(commandCheckConfig, "Check config file without contacting YouTrack, all problems are printed at once")
+	queryCommand := app.Command(commandQuery, "Run query once and print found issues with metrics which would be exported")
+	queryCommand.Arg("query", "Query name from config or YouTrack search query").Required().StringVar(&opts.query)
+	queryCommand.Flag("output", "Output format: table or json").Default(outputTable).EnumVar(&opts.output, outputTable, outputJSON)
 
-	b, err := ioutil.ReadFile(*configPath)
+	command, err := app.Parse(args)
+	if terminated {
+		return exitOK
+	}
 	if err != nil {
-		panic(err)
+		app.Errorf("%v, try --help", err)
+		return exitUsage
 	}
 
-	c, err := config.New(b)
+	if opts.once && command != commandRun {
+		app.Errorf("--once is supported by %v command only, try --help", commandRun)
+		return exitUsage
+	}
+
+	if command == commandCheckConfig {
+		return checkConfig(opts.configPath, stdout, stderr)
+	}
+
+	level, err := logging.ParseLevel(opts.logLevel)
+	if err != nil {
+		app.Errorf("%v", err)
+		return exitUsage
+	}
+
+	log, err := logging.New(stderr, opts.logFormat, level)
+	if err != nil {
+		app.Errorf("%v", err)
+		return exitUsage
+	}
+
+	switch {
+	case command == commandQuery:
+		return query(opts, stdout, log)
+	case opts.once:
+		return once(opts, log)
+	default:
+		return export(opts, log, nil)
+	}
+}
+
+// checkConfig prints all problems of config file.
+func checkConfig(configPath string, stdout, stderr io.Writer) int {
+	b, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "config read error: %v\n", err)
+		return exitConfigRead
+	}
+
+	problems := config.Check(b)
+	if c, err := config.New(b); err == nil {
+		// YouTrack client is not used by check, only its capabilities are
+		problems = append(problems, registerSources(monitoring.New(nil, nil, c, nil), new(youtrack.YouTrack))...)
+	}
+	if len(problems) > 0 {
+		_, _ = fmt.Fprintf(stderr, "config %v has %v problem(s):\n", configPath, len(problems))
+		for _, problem := range problems {
+			_, _ = fmt.Fprintf(stderr, "  - %v\n", problem)
+		}
+		return exitConfig
+	}
+
+	_, _ = fmt.Fprintf(stdout, "config %v is valid\n", configPath)
+	return exitOK
+}
+
+// export runs exporter until HTTP server fails or stop is closed, nil stop is never closed.
+func export(opts options, log *logging.Logger, stop <-chan struct{}) int {
+	c, code := readConfig(opts.configPath, log)
+	if code != exitOK {
+		return code
+	}
+
+	transport, err := newTransport(c)
 	if err != nil {
-		panic(err)
+		log.Error("http transport error", "error", err)
+		return exitSetup
 	}
 
+	// Listener is opened before metrics registration, so listen error is reported before any refresh
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%v", c.ListenPort))
+	if err != nil {
+		log.Error("listen error", "port", c.ListenPort, "error", err)
+		return exitListen
+	}
+	defer func() { _ = listener.Close() }()
+
+	// registry is created per run, so exporter may be run several times in one process
+	registry := pr.NewRegistry()
+	registry.MustRegister(pr.NewGoCollector(), pr.NewProcessCollector(pr.ProcessCollectorOpts{}))
+
 	var (
-		client       = httpwrap.New(&http.Client{Timeout: time.Duration(c.RequestTimeoutSeconds) * time.Second})
+		metrics      = prometheus.New(registry, c.ResolutionBuckets)
 		refreshDelay = time.Duration(c.RefreshDelaySeconds) * time.Second
 	)
 
-	yt, err := youtrack.New(c.Endpoint, c.Token, client)
+	yt, err := newYouTrack(c, transport, metrics, log)
 	if err != nil {
-		panic(err)
+		log.Error("youtrack client error", "error", err)
+		return exitSetup
 	}
 
-	monitor := monitoring.New(yt, prometheus.New(), c.Queries)
+	monitor, code := newMonitoring(c, yt, metrics, opts.configPath, log)
+	if code != exitOK {
+		return code
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/status", monitor.ServeStatus)
 
+	serveErr := make(chan error, 1)
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		panic(http.ListenAndServe(fmt.Sprintf(":%v", c.ListenPort), nil))
+		serveErr <- http.Serve(listener, mux)
 	}()
 
+	log.Info("exporter started", "listen_port", c.ListenPort, "queries", len(c.Queries), "boards", len(c.AgileBoards))
+
 	for {
 		monitor.RefreshMetrics()
-		time.Sleep(refreshDelay)
+
+		select {
+		case err = <-serveErr:
+			log.Error("http server error", "error", err)
+			return exitListen
+		case <-stop:
+			return exitOK
+		case <-time.After(refreshDelay):
+		}
+	}
+}
+
+// readConfig reads and parses config file, exit code is returned on error.
+func readConfig(configPath string, log *logging.Logger) (*config.Config, int) {
+	b, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		log.Error("config read error", "path", configPath, "error", err)
+		return nil, exitConfigRead
+	}
+
+	c, err := config.New(b)
+	if err != nil {
+		log.Error("config error", "path", configPath, "error", err)
+		return nil, exitConfig
+	}
+
+	return c, exitOK
+}
+
+// newMonitoring creates monitoring with registered issue sources, exit code is returned if query source is not supported.
+func newMonitoring(c *config.Config, yt *youtrack.YouTrack, metrics *prometheus.Metrics, configPath string, log *logging.Logger) (*monitoring.Monitoring, int) {
+	monitor := monitoring.New(yt, metrics, c, log)
+	problems := registerSources(monitor, yt)
+	for _, problem := range problems {
+		log.Error("config error", "path", configPath, "error", problem)
+	}
+	if len(problems) > 0 {
+		return nil, exitConfig
+	}
+	return monitor, exitOK
+}
+
+// registerSources registers issue sources selected by query source name and returns problems of queries sources.
+func registerSources(monitor *monitoring.Monitoring, yt *youtrack.YouTrack) []error {
+	monitor.RegisterSource(config.SourceYouTrack, yt)
+	monitor.RegisterSource(config.SourceFile, filesource.New())
+	return monitor.CheckSources()
+}
+
+func newTransport(c *config.Config) (*http.Transport, error) {
+	return httpwrap.NewTransport(httpwrap.TransportOptions{
+		Proxy:              c.Proxy,
+		CAFile:             c.TLS.CAFile,
+		CertFile:           c.TLS.CertFile,
+		KeyFile:            c.TLS.KeyFile,
+		MinVersion:         c.TLS.MinVersionID,
+		InsecureSkipVerify: c.TLS.InsecureSkipVerify,
+	})
+}
+
+// newYouTrack creates YouTrack client with configured HTTP client features and authorization.
+func newYouTrack(c *config.Config, transport http.RoundTripper, metrics *prometheus.Metrics, log *logging.Logger) (*youtrack.YouTrack, error) {
+	client := httpwrap.New(&http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(c.RequestTimeoutSeconds) * time.Second,
+	}).WithMaxBodySize(c.MaxBodyBytes).WithMetrics(metrics).WithLogger(log)
+
+	if rl := c.RateLimit; rl != nil {
+		client = client.WithLimiter(httpwrap.NewLimiter(rl.RequestsPerSecond, rl.Burst, rl.MaxConcurrentRequests, metrics))
+	}
+
+	if cb := c.CircuitBreaker; cb != nil {
+		client = client.WithBreaker(httpwrap.NewBreaker(cb.Failures, time.Duration(cb.CooldownSeconds)*time.Second, metrics))
+	}
+
+	if c.HTTPCache != nil {
+		client = client.WithCache(httpwrap.NewCache(c.HTTPCache.MaxEntries, c.HTTPCache.MaxBytes, metrics))
+	}
+
+	var authorizer youtrack.Authorizer
+	if c.Hub != nil {
+		hubClient := client
+		if rl := c.Hub.RateLimit; rl != nil {
+			hubClient = client.WithLimiter(httpwrap.NewLimiter(rl.RequestsPerSecond, rl.Burst, rl.MaxConcurrentRequests, metrics))
+		}
+		authorizer = youtrack.NewHubAuthorizer(c.Hub.URL, c.Hub.ClientID, c.Hub.ClientSecret, c.Hub.Scope, hubClient, metrics, log)
+	} else {
+		authorizer = youtrack.TokenAuthorizer(c.Token)
+	}
+
+	yt, err := youtrack.New(c.Endpoint, authorizer, client)
+	if err != nil {
+		return nil, err
 	}
+	return yt.WithLogger(log), nil
 }
@@ -0,0 +1,512 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase          string
+		args           []string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:          "help",
+			args:           []string{"--help"},
+			expectedCode:   exitOK,
+			expectedStdout: "usage: youtrack-issues-prometheus-exporter [<flags>]",
+			expectedStderr: "",
+		},
+		{
+			tcase:          "unknown flag",
+			args:           []string{"--unknown"},
+			expectedCode:   exitUsage,
+			expectedStdout: "",
+			expectedStderr: "youtrack-issues-prometheus-exporter: error: unknown long flag '--unknown', try --help",
+		},
+		{
+			tcase:          "invalid log level",
+			args:           []string{"--log.level", "verbose"},
+			expectedCode:   exitUsage,
+			expectedStdout: "",
+			expectedStderr: "enum value must be one of debug,info,warn,error, got 'verbose'",
+		},
+		{
+			tcase:          "config read error",
+			args:           []string{"-c", "testdata/missing.json"},
+			expectedCode:   exitConfigRead,
+			expectedStdout: "",
+			expectedStderr: `level=error msg="config read error" path=testdata/missing.json error="open testdata/missing.json: no such file or directory"`,
+		},
+		{
+			tcase:          "invalid config",
+			args:           []string{"-c", "testdata/invalid.json"},
+			expectedCode:   exitConfig,
+			expectedStdout: "",
+			expectedStderr: `level=error msg="config error" path=testdata/invalid.json error="empty endpoint"`,
+		},
+		{
+			tcase:          "transport error",
+			args:           []string{"-c", "testdata/missing_ca.json", "--log.format", "json"},
+			expectedCode:   exitSetup,
+			expectedStdout: "",
+			expectedStderr: `{"error":"CA file read error: open testdata/missing.pem: no such file or directory","level":"error","msg":"http transport error"`,
+		},
+		{
+			tcase:          "check valid config",
+			args:           []string{"check-config", "-c", "../../example/config.json"},
+			expectedCode:   exitOK,
+			expectedStdout: "config ../../example/config.json is valid\n",
+			expectedStderr: "",
+		},
+		{
+			tcase:          "check config problems",
+			args:           []string{"check-config", "-c", "testdata/problems.json"},
+			expectedCode:   exitConfig,
+			expectedStdout: "",
+			expectedStderr: "config testdata/problems.json has 2 problem(s):\n" +
+				"  - unknown field: queries.unresolved.titel\n" +
+				"  - query \"unresolved\": empty query string\n",
+		},
+		{
+			tcase:          "check config unsupported sources",
+			args:           []string{"check-config", "-c", "testdata/unsupported_source.json"},
+			expectedCode:   exitConfig,
+			expectedStdout: "",
+			expectedStderr: "config testdata/unsupported_source.json has 2 problem(s):\n" +
+				"  - query file_items: source file: not supported for type: work_items\n" +
+				"  - query jira: source is not registered: jira\n",
+		},
+		{
+			tcase:          "check config read error",
+			args:           []string{"check-config", "-c", "testdata/missing.json"},
+			expectedCode:   exitConfigRead,
+			expectedStdout: "",
+			expectedStderr: "config read error: open testdata/missing.json: no such file or directory\n",
+		},
+		{
+			tcase:          "unknown command",
+			args:           []string{"check"},
+			expectedCode:   exitUsage,
+			expectedStdout: "",
+			expectedStderr: "error: unexpected check, try --help",
+		},
+		{
+			tcase:          "once config read error",
+			args:           []string{"-c", "testdata/missing.json", "--once"},
+			expectedCode:   exitConfigRead,
+			expectedStdout: "",
+			expectedStderr: `level=error msg="config read error" path=testdata/missing.json`,
+		},
+		{
+			tcase:          "once with check config",
+			args:           []string{"check-config", "--once"},
+			expectedCode:   exitUsage,
+			expectedStdout: "",
+			expectedStderr: "error: --once is supported by run command only, try --help",
+		},
+		{
+			tcase:          "once with query",
+			args:           []string{"--once", "query", "#Unresolved"},
+			expectedCode:   exitUsage,
+			expectedStdout: "",
+			expectedStderr: "error: --once is supported by run command only, try --help",
+		},
+		{
+			tcase:          "once with run command",
+			args:           []string{"run", "-c", "testdata/missing.json", "--once"},
+			expectedCode:   exitConfigRead,
+			expectedStdout: "",
+			expectedStderr: `level=error msg="config read error" path=testdata/missing.json`,
+		},
+		{
+			tcase:          "query without argument",
+			args:           []string{"query"},
+			expectedCode:   exitUsage,
+			expectedStdout: "",
+			expectedStderr: "error: required argument 'query' not provided, try --help",
+		},
+		{
+			tcase:          "query config read error",
+			args:           []string{"-c", "testdata/missing.json", "query", "#Unresolved"},
+			expectedCode:   exitConfigRead,
+			expectedStdout: "",
+			expectedStderr: `level=error msg="config read error" path=testdata/missing.json`,
+		},
+	}
+
+	for _, testUnit := range testTable {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run(testUnit.args, stdout, stderr)
+		assert.Equal(t, testUnit.expectedCode, code, testUnit.tcase)
+		assert.Contains(t, stdout.String(), testUnit.expectedStdout, testUnit.tcase)
+		assert.Contains(t, stderr.String(), testUnit.expectedStderr, testUnit.tcase)
+	}
+}
+
+func TestRun_ListenError(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", ":0")
+	assert.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	dir, err := ioutil.TempDir("", "exporter")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	configPath := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(configPath, []byte(fmt.Sprintf(`{
+  "endpoint": "https://youtrack.company.com/",
+  "token": "perm:abc",
+  "queries": {"unresolved": {"query": "#Unresolved"}},
+  "listen_port": %v
+}`, listener.Addr().(*net.TCPAddr).Port)), 0600)
+	assert.NoError(t, err)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run([]string{"-c", configPath}, stdout, stderr)
+	assert.Equal(t, exitListen, code)
+	assert.Contains(t, stderr.String(), `level=error msg="listen error"`)
+	assert.Contains(t, stderr.String(), "address already in use")
+}
+
+func TestRun_Query(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/issues", r.URL.Path)
+		assert.Equal(t, "Show-Stopper #Unresolved", r.URL.Query().Get("query"))
+		_, _ = w.Write([]byte(`[
+    {"project": {"shortName": "YT"}, "summary": "Test issue 2", "numberInProject": 200},
+    {"project": {"shortName": "YT"}, "summary": "Test issue 1", "numberInProject": 100}
+]`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "exporter")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	configPath := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(configPath, []byte(fmt.Sprintf(`{
+  "endpoint": "%v/",
+  "token": "perm:abc",
+  "queries": {"showstopper": {"query": "Show-Stopper #Unresolved", "url_label": true}}
+}`, server.URL)), 0600)
+	assert.NoError(t, err)
+
+	// metrics are registered per run, so query may be run several times in one process
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run([]string{"-c", configPath, "query", "showstopper"}, stdout, stderr)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "", stderr.String())
+	assert.Contains(t, stdout.String(), "2 issue(s) found by query showstopper, exported metrics:\n")
+
+	stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	code = run([]string{"-c", configPath, "query", "showstopper", "--output", "json"}, stdout, stderr)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "", stderr.String())
+
+	issue := `youtrack_issues{id="YT-%v",project="",query="showstopper",title="Test issue %v",url="%v/issue/YT-%v"} 1`
+	expected := queryResult{
+		Query: "showstopper",
+		Count: 2,
+		Issues: []queryIssue{
+			{ID: "YT-100", Title: "Test issue 1", URL: server.URL + "/issue/YT-100"},
+			{ID: "YT-200", Title: "Test issue 2", URL: server.URL + "/issue/YT-200"},
+		},
+		Metrics: []string{
+			"# HELP youtrack_issues Query issues",
+			"# TYPE youtrack_issues gauge",
+			fmt.Sprintf(issue, 100, 1, server.URL, 100),
+			fmt.Sprintf(issue, 200, 2, server.URL, 200),
+		},
+	}
+
+	var result queryResult
+	err = json.Unmarshal(stdout.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+}
+
+func TestRun_QuerySource(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "exporter")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	issuesPath, err := filepath.Abs("../../filesource/testdata/issues.json")
+	assert.NoError(t, err)
+
+	// YouTrack is not contacted by file source queries
+	configPath := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(configPath, []byte(fmt.Sprintf(`{
+  "endpoint": "https://youtrack.company.com/",
+  "token": "perm:abc",
+  "queries": {
+    "file": {"source": "file", "query": %[1]q, "type": "count", "thresholds": {"warning": {"count": 2}}},
+    "file_items": {"source": "file", "query": %[1]q, "type": "work_items"}
+  }
+}`, issuesPath)), 0600)
+	assert.NoError(t, err)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run([]string{"-c", configPath, "query", "file"}, stdout, stderr)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "", stderr.String())
+	assert.Contains(t, stdout.String(), "2 issue(s) found by query file, exported metrics:\n")
+	assert.Contains(t, stdout.String(), `youtrack_query_issues{project="",query="file"} 2`)
+	assert.Contains(t, stdout.String(), `youtrack_query_status{query="file",severity="warning"} 1`)
+
+	stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	code = run([]string{"-c", configPath, "query", "file_items"}, stdout, stderr)
+	assert.Equal(t, exitConfig, code)
+	assert.Equal(t, "", stdout.String())
+	assert.Contains(t, stderr.String(), `level=error msg="config error" path=`+configPath+` error="query file_items: source file: not supported for type: work_items"`)
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"project": {"shortName": "YT"}, "summary": "Test issue", "numberInProject": 100}]`))
+	}))
+	defer server.Close()
+
+	// free port is got from listener which is closed before export
+	listener, err := net.Listen("tcp", ":0")
+	assert.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	_ = listener.Close()
+
+	dir, err := ioutil.TempDir("", "exporter")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	configPath := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(configPath, []byte(fmt.Sprintf(`{
+  "endpoint": "%v/",
+  "token": "perm:abc",
+  "queries": {"unresolved": {"query": "#Unresolved"}},
+  "listen_port": %v
+}`, server.URL, port)), 0600)
+	assert.NoError(t, err)
+
+	stderr := &bytes.Buffer{}
+	log, err := logging.New(stderr, logging.FormatLogfmt, logging.LevelInfo)
+	assert.NoError(t, err)
+
+	stop := make(chan struct{})
+	exited := make(chan int, 1)
+	go func() {
+		exited <- export(options{configPath: configPath}, log, stop)
+	}()
+
+	var metrics string
+	for i := 0; i < 100 && !strings.Contains(metrics, "youtrack_issues{"); i++ {
+		time.Sleep(10 * time.Millisecond)
+		metrics = get(t, fmt.Sprintf("http://localhost:%v/metrics", port))
+	}
+	assert.Contains(t, metrics, `youtrack_issues{id="YT-100",project="",query="unresolved",title="Test issue",url=""} 1`)
+	assert.Contains(t, metrics, "go_goroutines ")
+
+	close(stop)
+	assert.Equal(t, exitOK, <-exited)
+	assert.Contains(t, stderr.String(), `level=info msg="exporter started"`)
+}
+
+func TestRun_Once(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"project": {"shortName": "YT"}, "summary": "Test issue", "numberInProject": 100}]`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "exporter")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	configPath := filepath.Join(dir, "config.json")
+	err = ioutil.WriteFile(configPath, []byte(fmt.Sprintf(`{
+  "endpoint": "%v/",
+  "token": "perm:abc",
+  "queries": {"unresolved": {"query": "#Unresolved"}}
+}`, server.URL)), 0600)
+	assert.NoError(t, err)
+
+	// metrics are registered per run, so textfile may be written several times in one process
+	path := filepath.Join(dir, "youtrack_issues.prom")
+	for i := 0; i < 2; i++ {
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"-c", configPath, "--once", "--textfile.path", path}, stdout, stderr)
+		assert.Equal(t, exitOK, code)
+		assert.Contains(t, stderr.String(), `level=info msg="textfile written"`)
+
+		b, err := ioutil.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), `youtrack_issues{id="YT-100",project="",query="unresolved",title="Test issue",url=""} 1`)
+		assert.NotContains(t, string(b), "go_goroutines")
+	}
+}
+
+func TestRun_OnceFailed(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	type testTableData struct {
+		tcase    string
+		query    string
+		textfile string
+		code     int
+		stderr   string
+		metrics  string
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:    "refresh failed",
+			query:    `{"query": "#Unresolved"}`,
+			textfile: "youtrack_issues.prom",
+			code:     exitRefresh,
+			stderr:   `level=error msg="refresh failed"`,
+			metrics:  `youtrack_errors{error=`,
+		},
+		{
+			tcase:    "textfile not writable",
+			query:    `{"query": "#Unresolved"}`,
+			textfile: filepath.Join("missing", "youtrack_issues.prom"),
+			code:     exitTextfile,
+			stderr:   `level=error msg="textfile write error"`,
+		},
+		{
+			tcase:    "resolution query",
+			query:    `{"type": "resolution", "query": "#Resolved"}`,
+			textfile: "youtrack_issues.prom",
+			code:     exitConfig,
+			stderr:   "query unresolved: type resolution is not supported in one-shot mode",
+		},
+	}
+
+	for _, testUnit := range testTable {
+		dir, err := ioutil.TempDir("", "exporter")
+		assert.NoError(t, err, testUnit.tcase)
+
+		configPath := filepath.Join(dir, "config.json")
+		err = ioutil.WriteFile(configPath, []byte(fmt.Sprintf(`{
+  "endpoint": "%v/",
+  "token": "perm:abc",
+  "queries": {"unresolved": %v}
+}`, server.URL, testUnit.query)), 0600)
+		assert.NoError(t, err, testUnit.tcase)
+
+		path := filepath.Join(dir, testUnit.textfile)
+		stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+		code := run([]string{"-c", configPath, "--once", "--textfile.path", path}, stdout, stderr)
+		assert.Equal(t, testUnit.code, code, testUnit.tcase)
+		assert.Contains(t, stderr.String(), testUnit.stderr, testUnit.tcase)
+
+		b, err := ioutil.ReadFile(path)
+		if testUnit.metrics == "" {
+			assert.Error(t, err, testUnit.tcase)
+		} else {
+			assert.NoError(t, err, testUnit.tcase)
+			assert.Contains(t, string(b), testUnit.metrics, testUnit.tcase)
+		}
+
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func get(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestResolveQuery(t *testing.T) {
+	t.Parallel()
+
+	c := &config.Config{
+		Queries: map[string]config.Query{
+			"unresolved": {Query: "#Unresolved", Type: config.TypeCount},
+		},
+	}
+
+	type testTableData struct {
+		nameOrQuery   string
+		expectedName  string
+		expectedQuery config.Query
+	}
+
+	testTable := []testTableData{
+		{
+			nameOrQuery:   "unresolved",
+			expectedName:  "unresolved",
+			expectedQuery: config.Query{Query: "#Unresolved", Type: config.TypeCount},
+		},
+		{
+			nameOrQuery:   "#Resolved",
+			expectedName:  adHocQueryName,
+			expectedQuery: config.Query{Query: "#Resolved", Type: config.TypeIssues, Title: config.TitleLabel},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		name, q := resolveQuery(c, testUnit.nameOrQuery)
+		assert.Equal(t, testUnit.expectedName, name, testUnit.nameOrQuery)
+		assert.Equal(t, testUnit.expectedQuery, q, testUnit.nameOrQuery)
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	err := writeTable(buf, queryResult{
+		Query:   "test",
+		Count:   2,
+		Issues:  []queryIssue{{ID: "YT-1", Title: "Long issue title", URL: ""}, {ID: "YT-10", Title: "Title", URL: ""}},
+		Metrics: []string{`youtrack_query_issues_count{project="",query="test"} 2`},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `ID     TITLE             URL
+YT-1   Long issue title  
+YT-10  Title             
+
+2 issue(s) found by query test, exported metrics:
+youtrack_query_issues_count{project="",query="test"} 2
+`, buf.String())
+}
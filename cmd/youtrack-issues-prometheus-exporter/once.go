@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/prometheus"
+	pr "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// textfileMetricsPrefix selects exporter metrics, runtime metrics of process would collide with node_exporter ones.
+const textfileMetricsPrefix = "youtrack_"
+
+// once refreshes metrics once and writes them to textfile of node_exporter textfile collector.
+// Textfile is written even if some refreshes failed, so errors metric is exported.
+func once(opts options, log *logging.Logger) int {
+	c, code := readConfig(opts.configPath, log)
+	if code != exitOK {
+		return code
+	}
+
+	problems := checkOnce(c)
+	for _, problem := range problems {
+		log.Error("config error", "path", opts.configPath, "error", problem)
+	}
+	if len(problems) > 0 {
+		return exitConfig
+	}
+
+	transport, err := newTransport(c)
+	if err != nil {
+		log.Error("http transport error", "error", err)
+		return exitSetup
+	}
+
+	registry := pr.NewRegistry()
+	metrics := prometheus.New(registry, c.ResolutionBuckets)
+	yt, err := newYouTrack(c, transport, metrics, log)
+	if err != nil {
+		log.Error("youtrack client error", "error", err)
+		return exitSetup
+	}
+
+	monitor, code := newMonitoring(c, yt, metrics, opts.configPath, log)
+	if code != exitOK {
+		return code
+	}
+
+	failed := monitor.RefreshMetrics()
+
+	err = writeTextfile(registry, opts.textfilePath)
+	if err != nil {
+		log.Error("textfile write error", "path", opts.textfilePath, "error", err)
+		return exitTextfile
+	}
+
+	if failed > 0 {
+		log.Error("refresh failed", "path", opts.textfilePath, "failed", failed)
+		return exitRefresh
+	}
+
+	log.Info("textfile written", "path", opts.textfilePath)
+	return exitOK
+}
+
+// checkOnce returns problems of queries which can not be exported in one-shot mode.
+// Resolution queries only remember resolved issues on first refresh and every one-shot run is first refresh,
+// so such queries would never export anything.
+func checkOnce(c *config.Config) []error {
+	var problems []error
+
+	queries := make([]string, 0, len(c.Queries))
+	for name := range c.Queries {
+		queries = append(queries, name)
+	}
+	sort.Strings(queries)
+	for _, name := range queries {
+		if c.Queries[name].Type == config.TypeResolution {
+			problems = append(problems, fmt.Errorf("query %v: type %v is not supported in one-shot mode", name, config.TypeResolution))
+		}
+	}
+
+	templates := make([]string, 0, len(c.QueryTemplates))
+	for name := range c.QueryTemplates {
+		templates = append(templates, name)
+	}
+	sort.Strings(templates)
+	for _, name := range templates {
+		if c.QueryTemplates[name].Type == config.TypeResolution {
+			problems = append(problems, fmt.Errorf("query template %v: type %v is not supported in one-shot mode", name, config.TypeResolution))
+		}
+	}
+	return problems
+}
+
+// writeTextfile writes exporter metrics in text format atomically: temp file in the same directory is renamed to path,
+// so textfile collector never reads partially written file.
+func writeTextfile(gatherer pr.Gatherer, path string) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	// temp file name does not end with .prom, so it is ignored by textfile collector
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	for _, family := range families {
+		if !strings.HasPrefix(family.GetName(), textfileMetricsPrefix) {
+			continue
+		}
+		_, err = expfmt.MetricFamilyToText(tmp, family)
+		if err != nil {
+			_ = tmp.Close()
+			return err
+		}
+	}
+
+	// file is flushed to disk before rename, so renamed file is not empty after crash
+	err = tmp.Sync()
+	if err != nil {
+		_ = tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	// temp file is created readable by owner only
+	err = os.Chmod(tmp.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
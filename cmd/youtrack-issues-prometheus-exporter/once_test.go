@@ -0,0 +1,50 @@
+package main
+
+import (
+	pr "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteTextfile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "exporter")
+	assert.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	issues := pr.NewGaugeVec(pr.GaugeOpts{Subsystem: "youtrack", Name: "issues", Help: "Query issues"}, []string{"query", "id"})
+	issues.WithLabelValues("unresolved", "YT-1").Set(1)
+
+	registry := pr.NewRegistry()
+	registry.MustRegister(issues, pr.NewGoCollector())
+
+	path := filepath.Join(dir, "youtrack_issues.prom")
+	err = ioutil.WriteFile(path, []byte("stale"), 0644)
+	assert.NoError(t, err)
+
+	err = writeTextfile(registry, path)
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP youtrack_issues Query issues
+# TYPE youtrack_issues gauge
+youtrack_issues{id="YT-1",query="unresolved"} 1
+`, string(b))
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	// temp file is renamed
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+
+	err = writeTextfile(registry, filepath.Join(dir, "missing", "youtrack_issues.prom"))
+	assert.Error(t, err)
+}
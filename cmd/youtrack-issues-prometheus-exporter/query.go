@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/prometheus"
+	pr "github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// adHocQueryName is query label of search query which is not configured.
+const adHocQueryName = "query"
+
+type queryResult struct {
+	Query string `json:"query"`
+	// Count is found issues count, issues are listed for issues type query only
+	Count   int          `json:"count"`
+	Issues  []queryIssue `json:"issues"`
+	Metrics []string     `json:"metrics"`
+}
+
+type queryIssue struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// query refreshes configured query or search query once as exporter does and prints found issues with metrics which would be exported.
+func query(opts options, stdout io.Writer, log *logging.Logger) int {
+	c, code := readConfig(opts.configPath, log)
+	if code != exitOK {
+		return code
+	}
+
+	transport, err := newTransport(c)
+	if err != nil {
+		log.Error("http transport error", "error", err)
+		return exitSetup
+	}
+
+	registry := pr.NewRegistry()
+	metrics := prometheus.New(registry, c.ResolutionBuckets)
+	yt, err := newYouTrack(c, transport, metrics, log)
+	if err != nil {
+		log.Error("youtrack client error", "error", err)
+		return exitSetup
+	}
+
+	// Only the query is refreshed, so agile boards and project discovery are skipped
+	queryName, q := resolveQuery(c, opts.query)
+	c.Queries = map[string]config.Query{queryName: q}
+	c.QueryTemplates = nil
+	c.AgileBoards = nil
+	c.ProjectDiscovery = nil
+
+	monitor, code := newMonitoring(c, yt, metrics, opts.configPath, log)
+	if code != exitOK {
+		return code
+	}
+
+	count, issues, err := monitor.RefreshQuery(queryName)
+	if err != nil {
+		log.Error("query error", "query", queryName, "error", err)
+		return exitQuery
+	}
+
+	lines, err := queryMetrics(registry, queryName)
+	if err != nil {
+		log.Error("metrics gather error", "error", err)
+		return exitQuery
+	}
+
+	result := queryResult{
+		Query:   queryName,
+		Count:   count,
+		Issues:  sortedIssues(issues),
+		Metrics: lines,
+	}
+
+	if opts.output == outputJSON {
+		err = json.NewEncoder(stdout).Encode(result)
+	} else {
+		err = writeTable(stdout, result)
+	}
+	if err != nil {
+		log.Error("output error", "error", err)
+		return exitQuery
+	}
+
+	return exitOK
+}
+
+// resolveQuery returns configured query by name, other values are used as search query of issues type.
+func resolveQuery(c *config.Config, nameOrQuery string) (string, config.Query) {
+	if q, ok := c.Queries[nameOrQuery]; ok {
+		return nameOrQuery, q
+	}
+
+	return adHocQueryName, config.Query{
+		Query: nameOrQuery,
+		Type:  config.TypeIssues,
+		Title: config.TitleLabel,
+	}
+}
+
+// queryMetrics returns exposition lines of gathered metrics with query label equal to queryName.
+func queryMetrics(gatherer pr.Gatherer, queryName string) ([]string, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	for _, family := range families {
+		var found []*dto.Metric
+		for _, metric := range family.Metric {
+			if hasLabel(metric, "query", queryName) {
+				found = append(found, metric)
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+
+		filtered := *family
+		filtered.Metric = found
+		_, err = expfmt.MetricFamilyToText(&buf, &filtered)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if buf.Len() == 0 {
+		return []string{}, nil
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+func hasLabel(metric *dto.Metric, name, value string) bool {
+	for _, label := range metric.Label {
+		if label.GetName() == name && label.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedIssues(issues map[string]model.Issue) []queryIssue {
+	sorted := make([]queryIssue, 0, len(issues))
+	for _, issue := range issues {
+		sorted = append(sorted, queryIssue{ID: issue.ID, Title: issue.Title, URL: issue.URL})
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+func writeTable(w io.Writer, result queryResult) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tTITLE\tURL")
+	for _, issue := range result.Issues {
+		_, _ = fmt.Fprintf(tw, "%v\t%v\t%v\n", issue.ID, issue.Title, issue.URL)
+	}
+	err := tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "\n%v issue(s) found by query %v, exported metrics:\n", result.Count, result.Query)
+	if err != nil {
+		return err
+	}
+	for _, line := range result.Metrics {
+		_, err = fmt.Fprintln(w, line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Check validates raw config deeper than New: unknown and duplicate fields, query names which are exported
+// as label values and empty query strings are checked too. All found problems are returned at once.
+func Check(raw []byte) []error {
+	var value interface{}
+	err := json.Unmarshal(raw, &value)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	for _, key := range duplicateKeys(raw) {
+		problems = append(problems, fmt.Errorf("duplicate field: %v", key))
+	}
+	for _, field := range unknownFields("", value, reflect.TypeOf(Config{})) {
+		problems = append(problems, fmt.Errorf("unknown field: %v", field))
+	}
+
+	var config Config
+	err = json.Unmarshal(raw, &config)
+	if err != nil {
+		return append(problems, err)
+	}
+
+	problems = append(problems, checkNames(&config)...)
+	for _, err := range validate(&config) {
+		if !containsError(problems, err) {
+			problems = append(problems, err)
+		}
+	}
+
+	return problems
+}
+
+// checkNames checks names of queries, templates and agile boards which are exported as label values.
+func checkNames(config *Config) []error {
+	var problems []error
+
+	for _, name := range sortedKeys(config.Queries) {
+		if err := checkName(name); err != nil {
+			problems = append(problems, fmt.Errorf("query %q: %v", name, err))
+		}
+		if strings.TrimSpace(config.Queries[name].Query) == "" {
+			problems = append(problems, fmt.Errorf("query %q: empty query string", name))
+		}
+	}
+
+	for _, name := range sortedKeys(config.QueryTemplates) {
+		if err := checkName(name); err != nil {
+			problems = append(problems, fmt.Errorf("query template %q: %v", name, err))
+		}
+		if strings.TrimSpace(config.QueryTemplates[name].Query.Query) == "" {
+			problems = append(problems, fmt.Errorf("query template %q: empty query string", name))
+		}
+	}
+
+	// Board names are exported as board label of sprint metrics
+	boards := make(map[string]bool, len(config.AgileBoards))
+	for i, board := range config.AgileBoards {
+		if board.Name == "" {
+			continue
+		}
+		if err := checkName(board.Name); err != nil {
+			problems = append(problems, fmt.Errorf("agile board %v %q: %v", i, board.Name, err))
+		}
+		if boards[board.Name] {
+			problems = append(problems, fmt.Errorf("agile board %v %q: duplicate name", i, board.Name))
+		}
+		boards[board.Name] = true
+	}
+
+	return problems
+}
+
+// checkName checks that name is a readable label value.
+func checkName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return errors.New("empty name")
+	case !utf8.ValidString(name):
+		return errors.New("name is not valid UTF-8")
+	case strings.TrimSpace(name) != name:
+		return errors.New("name has leading or trailing spaces")
+	case strings.IndexFunc(name, unicode.IsControl) >= 0:
+		return errors.New("name has control characters")
+	default:
+		return nil
+	}
+}
+
+// duplicateKeys returns paths of keys which are repeated in the same JSON object, the last value is used by decoder.
+func duplicateKeys(raw []byte) []string {
+	var duplicates []string
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	_ = walkDuplicates(decoder, "", &duplicates)
+	return duplicates
+}
+
+func walkDuplicates(decoder *json.Decoder, path string, duplicates *[]string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		seen := make(map[string]bool)
+		for decoder.More() {
+			token, err = decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			if seen[key] {
+				*duplicates = append(*duplicates, joinPath(path, key))
+			}
+			seen[key] = true
+
+			err = walkDuplicates(decoder, joinPath(path, key), duplicates)
+			if err != nil {
+				return err
+			}
+		}
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			err = walkDuplicates(decoder, fmt.Sprintf("%v[%v]", path, i), duplicates)
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		return nil
+	}
+
+	// closing delimiter
+	_, err = decoder.Token()
+	return err
+}
+
+// unknownFields returns paths of JSON object fields which are not decoded to type t.
+// Field names are matched case-insensitively as decoder does.
+func unknownFields(path string, value interface{}, t reflect.Type) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			// queries and agile boards may be set as strings
+			return nil
+		}
+
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			fieldType, ok := fields[strings.ToLower(key)]
+			if !ok {
+				unknown = append(unknown, joinPath(path, key))
+				continue
+			}
+			unknown = append(unknown, unknownFields(joinPath(path, key), object[key], fieldType)...)
+		}
+	case reflect.Map:
+		object, _ := value.(map[string]interface{})
+		for _, key := range sortedKeys(object) {
+			unknown = append(unknown, unknownFields(joinPath(path, key), object[key], t.Elem())...)
+		}
+	case reflect.Slice:
+		array, _ := value.([]interface{})
+		for i, elem := range array {
+			unknown = append(unknown, unknownFields(fmt.Sprintf("%v[%v]", path, i), elem, t.Elem())...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields returns types of struct fields by lowercase JSON names, fields of embedded structs are included.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			for embeddedName, embeddedType := range jsonFields(field.Type) {
+				fields[embeddedName] = embeddedType
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys returns sorted keys of map with string keys.
+func sortedKeys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsError(errs []error, err error) bool {
+	for _, e := range errs {
+		if e.Error() == err.Error() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase            string
+		raw              string
+		expectedProblems []error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "valid",
+			raw: `{
+				"endpoint": "https://youtrack.test.com/",
+				"token": "perm:abc",
+				"queries": {
+					"unresolved": "#Unresolved",
+					"critical": {"query": "Priority: Critical", "type": "count", "Title": "none"},
+					"Backend": "project: BE"
+				},
+				"agile_boards": ["Backend", {"name": "Frontend"}]
+			}`,
+			expectedProblems: nil,
+		},
+		{
+			tcase:            "invalid json",
+			raw:              `{"endpoint": `,
+			expectedProblems: []error{json.Unmarshal([]byte(`{"endpoint": `), &struct{}{})},
+		},
+		{
+			tcase: "all problems",
+			raw: `{
+				"endpoint": "ftp://youtrack.test.com/",
+				"token": "perm:abc",
+				"tokn": "perm:abc",
+				"queries": {
+					"unresolved": "#Unresolved",
+					"unresolved": "#Unresolved #Unassigned",
+					"empty": {"query": " ", "tresholds": {}},
+					" spaced": "#Unresolved",
+					"project_discovery": "#Unresolved",
+					"Backend": "#Unresolved"
+				},
+				"query_templates": {
+					"": {"query": "project: {{.Project}}", "projects": ["BE"], "project": "BE"}
+				},
+				"agile_boards": ["Backend", "Backend", {"name": "Frontend", "estimation": "Points"}],
+				"tls": {"ca": "ca.pem"}
+			}`,
+			expectedProblems: []error{
+				errors.New("duplicate field: queries.unresolved"),
+				errors.New("unknown field: agile_boards[2].estimation"),
+				errors.New("unknown field: queries.empty.tresholds"),
+				errors.New("unknown field: tls.ca"),
+				errors.New("unknown field: tokn"),
+				errors.New(`query " spaced": name has leading or trailing spaces`),
+				errors.New(`query "empty": empty query string`),
+				errors.New(`query template "": empty name`),
+				errors.New(`agile board 1 "Backend": duplicate name`),
+				errors.New("invalid endpoint scheme: ftp"),
+				errors.New("query project_discovery: name is reserved for project discovery"),
+			},
+		},
+		{
+			tcase: "all sections problems",
+			raw: `{
+				"endpoint": "https://youtrack.test.com/",
+				"queries": {
+					"typo": {"query": "#Unresolved", "type": "counter"},
+					"title": {"query": "#Unresolved", "title": "full"},
+					"project_YT": "#Unresolved"
+				},
+				"query_templates": {
+					"project": {"query": "project: {{.Project}}"},
+					"broken": {"query": "project: {{.Project", "projects": ["BE"]}
+				},
+				"agile_boards": [{"name": ""}],
+				"project_discovery": {"filter": "("},
+				"proxy_url": "ftp://proxy.test.com"
+			}`,
+			expectedProblems: []error{
+				errors.New("empty token"),
+				errors.New("query title: unknown title mode: full"),
+				errors.New("query typo: unknown type: counter"),
+				errors.New("query template broken: template: broken:1: unclosed action"),
+				errors.New("query project_YT: name prefix project_ is reserved for project discovery"),
+				errors.New("query template project: name is reserved for project discovery"),
+				errors.New("agile board 0: empty name"),
+				errors.New("project discovery: invalid filter: error parsing regexp: missing closing ): `(`"),
+				errors.New("invalid proxy url scheme: ftp"),
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		problems := Check([]byte(testUnit.raw))
+		assert.Equal(t, testUnit.expectedProblems, problems, testUnit.tcase)
+	}
+}
+
+func TestCheckName(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		name        string
+		expectedErr error
+	}
+
+	testTable := []testTableData{
+		{
+			name:        "unresolved issues",
+			expectedErr: nil,
+		},
+		{
+			name:        " ",
+			expectedErr: errors.New("empty name"),
+		},
+		{
+			name:        "bad \xff",
+			expectedErr: errors.New("name is not valid UTF-8"),
+		},
+		{
+			name:        "new\tline",
+			expectedErr: errors.New("name has control characters"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		assert.Equal(t, testUnit.expectedErr, checkName(testUnit.name), testUnit.name)
+	}
+}
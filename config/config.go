@@ -1,27 +1,269 @@
 package config
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
 )
 
 // Config represents config for exporter.
 type Config struct {
-	Endpoint              string            `json:"endpoint"`
-	Token                 string            `json:"token"`
-	Queries               map[string]string `json:"queries"`
-	RefreshDelaySeconds   int               `json:"refresh_delay_seconds"`
-	RequestTimeoutSeconds int               `json:"request_timeout_seconds"`
-	ListenPort            int               `json:"listen_port"`
+	Endpoint              string                   `json:"endpoint"`
+	Token                 string                   `json:"token"`
+	Hub                   *Hub                     `json:"hub"`
+	Queries               map[string]Query         `json:"queries"`
+	QueryTemplates        map[string]QueryTemplate `json:"query_templates"`
+	AgileBoards           []AgileBoard             `json:"agile_boards"`
+	ProjectDiscovery      *ProjectDiscovery        `json:"project_discovery"`
+	RefreshDelaySeconds   int                      `json:"refresh_delay_seconds"`
+	RequestTimeoutSeconds int                      `json:"request_timeout_seconds"`
+	MaxBodyBytes          int64                    `json:"max_body_bytes"`
+	ResolutionBuckets     []float64                `json:"resolution_buckets"`
+	ProxyURL              string                   `json:"proxy_url"`
+	Proxy                 *url.URL                 `json:"-"`
+	TLS                   TLS                      `json:"tls"`
+	RateLimit             *RateLimit               `json:"rate_limit"`
+	CircuitBreaker        *CircuitBreaker          `json:"circuit_breaker"`
+	HTTPCache             *HTTPCache               `json:"http_cache"`
+	ListenPort            int                      `json:"listen_port"`
 }
 
+// Hub represents JetBrains Hub service OAuth2 client credentials used instead of permanent token.
+type Hub struct {
+	// URL of Hub service, YouTrack built-in Hub is used if empty
+	URL          string `json:"url"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Scope        string `json:"scope"`
+	// RateLimit of Hub requests, global rate limit is used if nil
+	RateLimit *RateLimit `json:"rate_limit"`
+}
+
+// TLS represents TLS settings of YouTrack and Hub requests.
+type TLS struct {
+	CAFile             string `json:"ca_file"`
+	CertFile           string `json:"cert_file"`
+	KeyFile            string `json:"key_file"`
+	MinVersion         string `json:"min_version"`
+	MinVersionID       uint16 `json:"-"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// RateLimit represents client-side limits of requests, zero value is not limited.
+type RateLimit struct {
+	RequestsPerSecond     float64 `json:"requests_per_second"`
+	Burst                 int     `json:"burst"`
+	MaxConcurrentRequests int     `json:"max_concurrent_requests"`
+}
+
+// CircuitBreaker represents circuit breaker of YouTrack and Hub requests.
+type CircuitBreaker struct {
+	// Failures is consecutive failed requests count which opens circuit breaker
+	Failures        int `json:"failures"`
+	CooldownSeconds int `json:"cooldown_seconds"`
+}
+
+// HTTPCache represents cache of YouTrack responses used for conditional requests.
+type HTTPCache struct {
+	MaxEntries int `json:"max_entries"`
+	// MaxBytes is max total size of cached response bodies
+	MaxBytes int64 `json:"max_bytes"`
+}
+
+// tlsVersions are supported TLS min versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Query represents search query with its export settings.
+type Query struct {
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Query           string `json:"query"`
+	Project         string `json:"project"`
+	Title           string `json:"title"`
+	TitleMaxLength  int    `json:"title_max_length"`
+	URLLabel        bool   `json:"url_label"`
+	EstimationField string `json:"estimation_field"`
+	// ResolutionWindowSeconds limits resolution queries to issues resolved in sliding window
+	ResolutionWindowSeconds int         `json:"resolution_window_seconds"`
+	SLA                     *SLA        `json:"sla"`
+	Thresholds              *Thresholds `json:"thresholds"`
+}
+
+// SLA represents service level agreement of issues query.
+type SLA struct {
+	ThresholdSeconds int `json:"threshold_seconds"`
+	// StartField is date custom field which starts SLA countdown, issue creation time is used if empty
+	StartField string `json:"start_field"`
+}
+
+// Query types.
+const (
+	// TypeIssues exports found issues.
+	TypeIssues = "issues"
+	// TypeWorkItems exports spent and estimated time of found issues.
+	TypeWorkItems = "work_items"
+	// TypeCount exports found issues count.
+	TypeCount = "count"
+	// TypeResolution exports resolution time histogram of found issues.
+	TypeResolution = "resolution"
+)
+
+// Issue sources registered by exporter, query source is checked against registered sources.
+const (
+	// SourceYouTrack gets issues from YouTrack, it is the default source.
+	SourceYouTrack = "youtrack"
+	// SourceFile reads issues from local JSON or CSV file, query is file path.
+	SourceFile = "file"
+)
+
+// Names of project discovery queries.
+const (
+	// DiscoveryQueryName is query label of project discovery errors.
+	DiscoveryQueryName = "project_discovery"
+	// ProjectQueryPrefix is prefix of count query names of discovered projects.
+	ProjectQueryPrefix = "project_"
+)
+
+// Title export modes.
 const (
-	defaultRequestTimeoutSeconds = 10
-	defaultRefreshDelaySeconds   = 10
-	defaultListenPort            = 8080
+	// TitleLabel exports title as label of issue metric.
+	TitleLabel = "label"
+	// TitleNone does not export title at all.
+	TitleNone = "none"
+	// TitleInfo exports title in separate info metric.
+	TitleInfo = "info"
 )
 
-// New creates Config instance.
+// UnmarshalJSON allows to set query both as search query string and as object with settings.
+func (q *Query) UnmarshalJSON(raw []byte) error {
+	var query string
+	if json.Unmarshal(raw, &query) == nil {
+		*q = Query{Query: query}
+		return nil
+	}
+
+	type plainQuery Query
+	return json.Unmarshal(raw, (*plainQuery)(q))
+}
+
+// Thresholds represents query status thresholds.
+type Thresholds struct {
+	Warning  *Threshold `json:"warning"`
+	Critical *Threshold `json:"critical"`
+}
+
+// HasAge reports whether issues creation time is required for thresholds, nil thresholds have no age.
+func (t *Thresholds) HasAge() bool {
+	if t == nil {
+		return false
+	}
+	return (t.Warning != nil && t.Warning.AgeSeconds > 0) || (t.Critical != nil && t.Critical.AgeSeconds > 0)
+}
+
+// Threshold is reached if found issues count or the oldest issue age reaches its value, zero value is not checked.
+type Threshold struct {
+	Count      int `json:"count"`
+	AgeSeconds int `json:"age_seconds"`
+}
+
+// QueryTemplate represents query which is expanded for each project.
+// Query string is a text/template with {{.Project}} placeholder.
+// Discovered projects are used if Projects is empty.
+type QueryTemplate struct {
+	Query
+	Projects []string           `json:"projects"`
+	Template *template.Template `json:"-"`
+}
+
+// UnmarshalJSON allows to set query template both as search query string and as object with settings.
+func (t *QueryTemplate) UnmarshalJSON(raw []byte) error {
+	err := json.Unmarshal(raw, &t.Query)
+	if err != nil {
+		return err
+	}
+
+	var projects struct {
+		Projects []string `json:"projects"`
+	}
+	if json.Unmarshal(raw, &projects) == nil {
+		t.Projects = projects.Projects
+	}
+	return nil
+}
+
+// templateData is passed to query template.
+type templateData struct {
+	Project string
+}
+
+// Expand returns query name and query of template for project.
+func (t QueryTemplate) Expand(name, project string) (string, Query, error) {
+	var b strings.Builder
+	err := t.Template.Execute(&b, templateData{Project: project})
+	if err != nil {
+		return "", Query{}, err
+	}
+
+	query := t.Query
+	query.Query = b.String()
+	query.Project = project
+	return name + "_" + project, query, nil
+}
+
+// AgileBoard represents agile board which current sprint is exported.
+type AgileBoard struct {
+	Name            string `json:"name"`
+	EstimationField string `json:"estimation_field"`
+}
+
+// UnmarshalJSON allows to set agile board both as board name and as object with settings.
+func (b *AgileBoard) UnmarshalJSON(raw []byte) error {
+	var name string
+	if json.Unmarshal(raw, &name) == nil {
+		*b = AgileBoard{Name: name}
+		return nil
+	}
+
+	type plainAgileBoard AgileBoard
+	return json.Unmarshal(raw, (*plainAgileBoard)(b))
+}
+
+// ProjectDiscovery represents settings of projects discovery.
+// Count query is generated for each discovered project.
+type ProjectDiscovery struct {
+	Filter          string         `json:"filter"`
+	FilterRegexp    *regexp.Regexp `json:"-"`
+	IncludeArchived bool           `json:"include_archived"`
+	Query           string         `json:"query"`
+}
+
+const (
+	defaultRequestTimeoutSeconds   = 10
+	defaultRefreshDelaySeconds     = 10
+	defaultListenPort              = 8080
+	defaultEstimationField         = "Estimation"
+	defaultResolutionWindowSeconds = 7 * 24 * 60 * 60
+	defaultBreakerFailures         = 5
+	defaultBreakerCooldownSeconds  = 60
+	defaultCacheMaxEntries         = 1000
+	defaultCacheMaxBytes           = 16 << 20
+	defaultMaxBodyBytes            = 64 << 20
+)
+
+// defaultBuckets are resolution time histogram buckets in seconds: from 1 hour to 30 days.
+var defaultBuckets = []float64{3600, 4 * 3600, 8 * 3600, 24 * 3600, 3 * 24 * 3600, 7 * 24 * 3600, 14 * 24 * 3600, 30 * 24 * 3600}
+
+// New creates Config instance, the first problem of config is returned.
 func New(raw []byte) (*Config, error) {
 	var config Config
 	err := json.Unmarshal(raw, &config)
@@ -29,22 +271,85 @@ func New(raw []byte) (*Config, error) {
 		return nil, err
 	}
 
+	problems := validate(&config)
+	if len(problems) > 0 {
+		return nil, problems[0]
+	}
+
+	return &config, nil
+}
+
+// validate checks config and sets defaults. Problems of each section, query, template and board are returned,
+// so all of them are reported by Check.
+func validate(config *Config) []error {
+	var problems []error
+
 	if config.Endpoint == "" {
-		return nil, errors.New("empty endpoint")
+		problems = append(problems, errors.New("empty endpoint"))
+	} else if err := checkEndpoint(config.Endpoint); err != nil {
+		problems = append(problems, err)
+	}
+
+	if err := checkAuth(config); err != nil {
+		problems = append(problems, err)
 	}
 
-	if config.Token == "" {
-		return nil, errors.New("empty token")
+	if len(config.Queries) == 0 && len(config.QueryTemplates) == 0 &&
+		len(config.AgileBoards) == 0 && config.ProjectDiscovery == nil {
+		problems = append(problems, errors.New("empty queries"))
 	}
 
-	if len(config.Queries) == 0 {
-		return nil, errors.New("empty queries")
+	for _, name := range sortedKeys(config.Queries) {
+		query, err := fixQuery(config.Queries[name])
+		if err != nil {
+			problems = append(problems, fmt.Errorf("query %v: %v", name, err))
+			continue
+		}
+		config.Queries[name] = query
+	}
+
+	problems = append(problems, expandTemplates(config)...)
+	problems = append(problems, checkReservedNames(config)...)
+
+	for i, board := range config.AgileBoards {
+		if board.Name == "" {
+			problems = append(problems, fmt.Errorf("agile board %v: empty name", i))
+		}
+		if board.EstimationField == "" {
+			config.AgileBoards[i].EstimationField = defaultEstimationField
+		}
+	}
+
+	if config.ProjectDiscovery != nil {
+		var err error
+		config.ProjectDiscovery.FilterRegexp, err = regexp.Compile(config.ProjectDiscovery.Filter)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("project discovery: invalid filter: %v", err))
+		}
+	}
+
+	if err := checkTransport(config); err != nil {
+		problems = append(problems, err)
 	}
 
 	if config.RequestTimeoutSeconds <= 0 {
 		config.RequestTimeoutSeconds = defaultRequestTimeoutSeconds
 	}
 
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultMaxBodyBytes
+	}
+
+	if len(config.ResolutionBuckets) == 0 {
+		config.ResolutionBuckets = defaultBuckets
+	}
+	for i := 1; i < len(config.ResolutionBuckets); i++ {
+		if config.ResolutionBuckets[i] <= config.ResolutionBuckets[i-1] {
+			problems = append(problems, errors.New("resolution buckets are not in increasing order"))
+			break
+		}
+	}
+
 	if config.RefreshDelaySeconds <= 0 {
 		config.RefreshDelaySeconds = defaultRefreshDelaySeconds
 	}
@@ -53,5 +358,296 @@ func New(raw []byte) (*Config, error) {
 		config.ListenPort = defaultListenPort
 	}
 
-	return &config, nil
+	return problems
+}
+
+// checkAuth checks that exactly one of permanent token and Hub credentials is set.
+func checkAuth(config *Config) error {
+	if config.Hub == nil {
+		if config.Token == "" {
+			return errors.New("empty token")
+		}
+		return nil
+	}
+
+	if config.Token != "" {
+		return errors.New("token and hub are both set")
+	}
+
+	hub := config.Hub
+	if hub.URL == "" {
+		hub.URL = strings.TrimSuffix(config.Endpoint, "/") + "/hub"
+	}
+
+	err := checkEndpoint(hub.URL)
+	if err != nil {
+		return fmt.Errorf("hub: %v", err)
+	}
+
+	switch {
+	case hub.ClientID == "":
+		return errors.New("hub: empty client_id")
+	case hub.ClientSecret == "":
+		return errors.New("hub: empty client_secret")
+	case hub.Scope == "":
+		return errors.New("hub: empty scope")
+	}
+
+	err = checkRateLimit(hub.RateLimit)
+	if err != nil {
+		return fmt.Errorf("hub: rate limit: %v", err)
+	}
+
+	return nil
+}
+
+// checkTransport parses proxy URL and TLS min version, checks rate limit and fixes circuit breaker and cache defaults.
+func checkTransport(config *Config) error {
+	if config.ProxyURL != "" {
+		proxy, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy url: %v", err)
+		}
+		if proxy.Scheme != "http" && proxy.Scheme != "https" && proxy.Scheme != "socks5" {
+			return fmt.Errorf("invalid proxy url scheme: %v", proxy.Scheme)
+		}
+		config.Proxy = proxy
+	}
+
+	if (config.TLS.CertFile == "") != (config.TLS.KeyFile == "") {
+		return errors.New("tls: cert_file and key_file must be set together")
+	}
+
+	err := checkRateLimit(config.RateLimit)
+	if err != nil {
+		return fmt.Errorf("rate limit: %v", err)
+	}
+
+	if cb := config.CircuitBreaker; cb != nil {
+		if cb.Failures < 0 || cb.CooldownSeconds < 0 {
+			return errors.New("circuit breaker: negative value")
+		}
+		if cb.Failures == 0 {
+			cb.Failures = defaultBreakerFailures
+		}
+		if cb.CooldownSeconds == 0 {
+			cb.CooldownSeconds = defaultBreakerCooldownSeconds
+		}
+	}
+
+	if cache := config.HTTPCache; cache != nil {
+		if cache.MaxEntries < 0 {
+			return errors.New("http cache: negative max entries")
+		}
+		if cache.MaxBytes < 0 {
+			return errors.New("http cache: negative max bytes")
+		}
+		if cache.MaxEntries == 0 {
+			cache.MaxEntries = defaultCacheMaxEntries
+		}
+		if cache.MaxBytes == 0 {
+			cache.MaxBytes = defaultCacheMaxBytes
+		}
+	}
+
+	if config.TLS.MinVersion != "" {
+		version, ok := tlsVersions[config.TLS.MinVersion]
+		if !ok {
+			return fmt.Errorf("tls: unsupported min version: %v", config.TLS.MinVersion)
+		}
+		config.TLS.MinVersionID = version
+	}
+
+	return nil
+}
+
+func checkRateLimit(rl *RateLimit) error {
+	if rl != nil && (rl.RequestsPerSecond < 0 || rl.Burst < 0 || rl.MaxConcurrentRequests < 0) {
+		return errors.New("negative value")
+	}
+	return nil
+}
+
+func checkEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid endpoint: %v", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid endpoint scheme: %v", u.Scheme)
+	}
+
+	if u.Host == "" {
+		return errors.New("empty endpoint host")
+	}
+
+	return nil
+}
+
+// expandTemplates parses query templates and adds queries of templates with configured projects.
+// Templates with problems are not expanded.
+func expandTemplates(config *Config) []error {
+	var problems []error
+
+	// Templates are expanded in the same order, so the same duplicate is reported
+	for _, name := range sortedKeys(config.QueryTemplates) {
+		err := expandTemplate(config, name)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("query template %v: %v", name, err))
+		}
+	}
+
+	return problems
+}
+
+func expandTemplate(config *Config, name string) error {
+	tmpl := config.QueryTemplates[name]
+	var err error
+	tmpl.Query, err = fixQuery(tmpl.Query)
+	if err != nil {
+		return err
+	}
+
+	tmpl.Template, err = template.New(name).Option("missingkey=error").Parse(tmpl.Query.Query)
+	if err != nil {
+		return err
+	}
+
+	// Check template execution before use
+	_, _, err = tmpl.Expand(name, "")
+	if err != nil {
+		return err
+	}
+
+	if len(tmpl.Projects) == 0 && config.ProjectDiscovery == nil {
+		return errors.New("empty projects and project discovery is disabled")
+	}
+
+	config.QueryTemplates[name] = tmpl
+
+	for _, project := range tmpl.Projects {
+		queryName, query, _ := tmpl.Expand(name, project)
+		if _, ok := config.Queries[queryName]; ok {
+			return fmt.Errorf("duplicate query %v", queryName)
+		}
+		if config.Queries == nil {
+			config.Queries = make(map[string]Query)
+		}
+		config.Queries[queryName] = query
+	}
+
+	return nil
+}
+
+// checkReservedNames checks that query names do not collide with names of project discovery queries.
+func checkReservedNames(config *Config) []error {
+	var problems []error
+
+	if _, ok := config.Queries[DiscoveryQueryName]; ok {
+		problems = append(problems, fmt.Errorf("query %v: name is reserved for project discovery", DiscoveryQueryName))
+	}
+
+	if config.ProjectDiscovery == nil {
+		return problems
+	}
+
+	for _, name := range sortedKeys(config.Queries) {
+		if name != DiscoveryQueryName && strings.HasPrefix(name, ProjectQueryPrefix) {
+			problems = append(problems, fmt.Errorf("query %v: name prefix %v is reserved for project discovery", name, ProjectQueryPrefix))
+		}
+	}
+
+	// Template queries are named as <template>_<project>, so they may collide with discovered project queries
+	for _, name := range sortedKeys(config.QueryTemplates) {
+		if name+"_" == ProjectQueryPrefix || strings.HasPrefix(name, ProjectQueryPrefix) {
+			problems = append(problems, fmt.Errorf("query template %v: name is reserved for project discovery", name))
+		}
+	}
+
+	return problems
+}
+
+func fixQuery(query Query) (Query, error) {
+	switch query.Type {
+	case "":
+		query.Type = TypeIssues
+	case TypeIssues, TypeCount:
+	case TypeWorkItems:
+		if query.EstimationField == "" {
+			query.EstimationField = defaultEstimationField
+		}
+	case TypeResolution:
+		if query.ResolutionWindowSeconds <= 0 {
+			query.ResolutionWindowSeconds = defaultResolutionWindowSeconds
+		}
+	default:
+		return query, fmt.Errorf("unknown type: %v", query.Type)
+	}
+
+	switch query.Title {
+	case "":
+		query.Title = TitleLabel
+	case TitleLabel, TitleNone, TitleInfo:
+	default:
+		return query, fmt.Errorf("unknown title mode: %v", query.Title)
+	}
+
+	if query.TitleMaxLength < 0 {
+		return query, errors.New("negative title max length")
+	}
+
+	if query.Thresholds != nil {
+		err := checkThresholds(query)
+		if err != nil {
+			return query, fmt.Errorf("thresholds: %v", err)
+		}
+	}
+
+	if query.SLA != nil {
+		if query.Type != TypeIssues {
+			return query, fmt.Errorf("sla is not supported for type: %v", query.Type)
+		}
+		if query.SLA.ThresholdSeconds <= 0 {
+			return query, errors.New("sla: non-positive threshold")
+		}
+	}
+
+	return query, nil
+}
+
+func checkThresholds(query Query) error {
+	if query.Type != TypeIssues && query.Type != TypeCount {
+		return fmt.Errorf("not supported for type: %v", query.Type)
+	}
+
+	if query.Thresholds.Warning == nil && query.Thresholds.Critical == nil {
+		return errors.New("empty thresholds")
+	}
+
+	thresholds := []struct {
+		severity  string
+		threshold *Threshold
+	}{
+		{severity: "warning", threshold: query.Thresholds.Warning},
+		{severity: "critical", threshold: query.Thresholds.Critical},
+	}
+
+	for _, t := range thresholds {
+		severity, threshold := t.severity, t.threshold
+		if threshold == nil {
+			continue
+		}
+		if threshold.Count < 0 || threshold.AgeSeconds < 0 {
+			return fmt.Errorf("%v: negative value", severity)
+		}
+		if threshold.Count == 0 && threshold.AgeSeconds == 0 {
+			return fmt.Errorf("%v: empty threshold", severity)
+		}
+		if threshold.AgeSeconds > 0 && query.Type != TypeIssues {
+			return fmt.Errorf("%v: age is supported only for type: %v", severity, TypeIssues)
+		}
+	}
+
+	return nil
 }
@@ -1,10 +1,17 @@
 package config
 
 import (
+	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
 	"testing"
+	"text/template"
 )
 
 func TestNew(t *testing.T) {
@@ -29,14 +36,18 @@ func TestNew(t *testing.T) {
   },
   "refresh_delay_seconds": 20,
   "request_timeout_seconds": 30,
+  "max_body_bytes": 1048576,
+  "resolution_buckets": [60, 3600],
   "listen_port": 9090
 }`),
 			expectedConfig: &Config{
 				Endpoint:              "http://www.test.com",
 				Token:                 "abc",
-				Queries:               map[string]string{"test": "test query"},
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
 				RefreshDelaySeconds:   20,
 				RequestTimeoutSeconds: 30,
+				MaxBodyBytes:          1048576,
+				ResolutionBuckets:     []float64{60, 3600},
 				ListenPort:            9090,
 			},
 			expectedErr: nil,
@@ -53,48 +64,984 @@ func TestNew(t *testing.T) {
 			expectedConfig: nil,
 			expectedErr:    errors.New("empty endpoint"),
 		},
+		{
+			tcase: "endpoint parse error",
+			raw: []byte(`
+{
+  "endpoint": "http://www test com/",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    fmt.Errorf("invalid endpoint: %v", &url.Error{Op: "parse", URL: "http://www test com/", Err: url.InvalidHostError(" ")}),
+		},
+		{
+			tcase: "invalid endpoint scheme",
+			raw: []byte(`
+{
+  "endpoint": "ftp://www.test.com/",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("invalid endpoint scheme: ftp"),
+		},
+		{
+			tcase: "endpoint without host",
+			raw: []byte(`
+{
+  "endpoint": "www.test.com/youtrack/",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("invalid endpoint scheme: "),
+		},
+		{
+			tcase: "empty endpoint host",
+			raw: []byte(`
+{
+  "endpoint": "https:///youtrack/",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("empty endpoint host"),
+		},
 		{
 			tcase: "empty token",
 			raw: []byte(`
 {
   "endpoint": "http://www.test.com",
   "queries": {
-    "test": "test query"
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("empty token"),
+		},
+		{
+			tcase: "hub",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com/youtrack/",
+  "hub": {
+    "client_id": "client",
+    "client_secret": "secret",
+    "scope": "0-0-0-0-0"
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: &Config{
+				Endpoint: "http://www.test.com/youtrack/",
+				Hub: &Hub{
+					URL:          "http://www.test.com/youtrack/hub",
+					ClientID:     "client",
+					ClientSecret: "secret",
+					Scope:        "0-0-0-0-0",
+				},
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "token and hub",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "hub": {
+    "url": "https://hub.test.com",
+    "client_id": "client",
+    "client_secret": "secret",
+    "scope": "0-0-0-0-0"
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("token and hub are both set"),
+		},
+		{
+			tcase: "hub rate limit",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "hub": {
+    "client_id": "client",
+    "client_secret": "secret",
+    "scope": "0-0-0-0-0",
+    "rate_limit": {
+      "requests_per_second": 1
+    }
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: &Config{
+				Endpoint: "http://www.test.com",
+				Hub: &Hub{
+					URL:          "http://www.test.com/hub",
+					ClientID:     "client",
+					ClientSecret: "secret",
+					Scope:        "0-0-0-0-0",
+					RateLimit:    &RateLimit{RequestsPerSecond: 1},
+				},
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "hub negative rate limit",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "hub": {
+    "client_id": "client",
+    "client_secret": "secret",
+    "scope": "0-0-0-0-0",
+    "rate_limit": {
+      "burst": -1
+    }
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("hub: rate limit: negative value"),
+		},
+		{
+			tcase: "hub invalid url",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "hub": {
+    "url": "ftp://hub.test.com",
+    "client_id": "client",
+    "client_secret": "secret",
+    "scope": "0-0-0-0-0"
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("hub: invalid endpoint scheme: ftp"),
+		},
+		{
+			tcase: "hub empty client_id",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "hub": {
+    "client_secret": "secret",
+    "scope": "0-0-0-0-0"
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("hub: empty client_id"),
+		},
+		{
+			tcase: "hub empty client_secret",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "hub": {
+    "client_id": "client",
+    "scope": "0-0-0-0-0"
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("hub: empty client_secret"),
+		},
+		{
+			tcase: "hub empty scope",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "hub": {
+    "client_id": "client",
+    "client_secret": "secret"
+  },
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("hub: empty scope"),
+		},
+		{
+			tcase: "transport",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "proxy_url": "http://proxy.test.com:3128",
+  "tls": {
+    "ca_file": "/etc/ssl/ca.pem",
+    "cert_file": "/etc/ssl/client.pem",
+    "key_file": "/etc/ssl/client-key.pem",
+    "min_version": "1.2",
+    "insecure_skip_verify": true
+  }
+}`),
+			expectedConfig: &Config{
+				Endpoint:              "http://www.test.com",
+				Token:                 "abc",
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+				ProxyURL:              "http://proxy.test.com:3128",
+				Proxy:                 &url.URL{Scheme: "http", Host: "proxy.test.com:3128"},
+				TLS: TLS{
+					CAFile:             "/etc/ssl/ca.pem",
+					CertFile:           "/etc/ssl/client.pem",
+					KeyFile:            "/etc/ssl/client-key.pem",
+					MinVersion:         "1.2",
+					MinVersionID:       tls.VersionTLS12,
+					InsecureSkipVerify: true,
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "invalid proxy url scheme",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "proxy_url": "ftp://proxy.test.com"
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("invalid proxy url scheme: ftp"),
+		},
+		{
+			tcase: "tls cert without key",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "tls": {
+    "cert_file": "/etc/ssl/client.pem"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("tls: cert_file and key_file must be set together"),
+		},
+		{
+			tcase: "tls unsupported min version",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "tls": {
+    "min_version": "1.4"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("tls: unsupported min version: 1.4"),
+		},
+		{
+			tcase: "rate limit",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "rate_limit": {
+    "requests_per_second": 2.5,
+    "burst": 5,
+    "max_concurrent_requests": 2
+  }
+}`),
+			expectedConfig: &Config{
+				Endpoint:              "http://www.test.com",
+				Token:                 "abc",
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+				RateLimit:             &RateLimit{RequestsPerSecond: 2.5, Burst: 5, MaxConcurrentRequests: 2},
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "negative rate limit",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "rate_limit": {
+    "max_concurrent_requests": -1
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("rate limit: negative value"),
+		},
+		{
+			tcase: "circuit breaker defaults",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "circuit_breaker": {}
+}`),
+			expectedConfig: &Config{
+				Endpoint:              "http://www.test.com",
+				Token:                 "abc",
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+				CircuitBreaker:        &CircuitBreaker{Failures: 5, CooldownSeconds: 60},
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "negative circuit breaker",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "circuit_breaker": {
+    "failures": 3,
+    "cooldown_seconds": -1
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("circuit breaker: negative value"),
+		},
+		{
+			tcase: "http cache defaults",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "http_cache": {}
+}`),
+			expectedConfig: &Config{
+				Endpoint:              "http://www.test.com",
+				Token:                 "abc",
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+				HTTPCache:             &HTTPCache{MaxEntries: 1000, MaxBytes: 16777216},
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "negative http cache max entries",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "http_cache": {
+    "max_entries": -1
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("http cache: negative max entries"),
+		},
+		{
+			tcase: "negative http cache max bytes",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "http_cache": {
+    "max_bytes": -1
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("http cache: negative max bytes"),
+		},
+		{
+			tcase: "empty queries",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc"
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("empty queries"),
+		},
+		{
+			tcase: "fix default values",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  }
+}`),
+			expectedConfig: &Config{
+				Endpoint:              "http://www.test.com",
+				Token:                 "abc",
+				Queries:               map[string]Query{"test": {Type: TypeIssues, Query: "test query", Title: TitleLabel}},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "query settings",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {
+      "query": "test query",
+      "title": "info",
+      "title_max_length": 50,
+      "url_label": true
+    },
+    "time": {
+      "type": "work_items",
+      "query": "#Resolved"
+    },
+    "resolution": {
+      "type": "resolution",
+      "query": "Type: Bug"
+    },
+    "critical resolution": {
+      "type": "resolution",
+      "query": "Type: Bug Priority: Critical",
+      "resolution_window_seconds": 3600
+    },
+    "critical": {
+      "query": "#Unresolved Priority: Critical",
+      "sla": {
+        "threshold_seconds": 7200,
+        "start_field": "Reopened"
+      },
+      "thresholds": {
+        "warning": {"count": 5, "age_seconds": 3600},
+        "critical": {"count": 10}
+      }
+    },
+    "offline": {
+      "source": "file",
+      "query": "/data/issues.csv"
+    },
+    "count": {
+      "type": "count",
+      "query": "#Unresolved",
+      "thresholds": {
+        "critical": {"count": 100}
+      }
+    }
   }
 }`),
-			expectedConfig: nil,
-			expectedErr:    errors.New("empty token"),
+			expectedConfig: &Config{
+				Endpoint: "http://www.test.com",
+				Token:    "abc",
+				Queries: map[string]Query{
+					"test": {Type: TypeIssues, Query: "test query", Title: TitleInfo, TitleMaxLength: 50, URLLabel: true},
+					"time": {Type: TypeWorkItems, Query: "#Resolved", Title: TitleLabel, EstimationField: "Estimation"},
+					"resolution": {
+						Type:                    TypeResolution,
+						Query:                   "Type: Bug",
+						Title:                   TitleLabel,
+						ResolutionWindowSeconds: 604800,
+					},
+					"critical resolution": {
+						Type:                    TypeResolution,
+						Query:                   "Type: Bug Priority: Critical",
+						Title:                   TitleLabel,
+						ResolutionWindowSeconds: 3600,
+					},
+					"critical": {
+						Type:  TypeIssues,
+						Query: "#Unresolved Priority: Critical",
+						Title: TitleLabel,
+						SLA:   &SLA{ThresholdSeconds: 7200, StartField: "Reopened"},
+						Thresholds: &Thresholds{
+							Warning:  &Threshold{Count: 5, AgeSeconds: 3600},
+							Critical: &Threshold{Count: 10},
+						},
+					},
+					"offline": {Source: SourceFile, Type: TypeIssues, Query: "/data/issues.csv", Title: TitleLabel},
+					"count": {
+						Type:       TypeCount,
+						Query:      "#Unresolved",
+						Title:      TitleLabel,
+						Thresholds: &Thresholds{Critical: &Threshold{Count: 100}},
+					},
+				},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+			},
+			expectedErr: nil,
 		},
 		{
-			tcase: "empty queries",
+			tcase: "agile boards only",
 			raw: []byte(`
 {
   "endpoint": "http://www.test.com",
-  "token": "abc"
+  "token": "abc",
+  "agile_boards": [
+    "Backend",
+    {
+      "name": "Frontend",
+      "estimation_field": "Story points"
+    }
+  ]
 }`),
-			expectedConfig: nil,
-			expectedErr:    errors.New("empty queries"),
+			expectedConfig: &Config{
+				Endpoint: "http://www.test.com",
+				Token:    "abc",
+				AgileBoards: []AgileBoard{
+					{Name: "Backend", EstimationField: "Estimation"},
+					{Name: "Frontend", EstimationField: "Story points"},
+				},
+				RefreshDelaySeconds:   10,
+				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
+				ListenPort:            8080,
+			},
+			expectedErr: nil,
 		},
 		{
-			tcase: "fix default values",
+			tcase: "project discovery only",
 			raw: []byte(`
 {
   "endpoint": "http://www.test.com",
   "token": "abc",
-  "queries": {
-    "test": "test query"
+  "project_discovery": {
+    "filter": "^(BE|FE)$",
+    "query": "#Unresolved"
   }
 }`),
 			expectedConfig: &Config{
-				Endpoint:              "http://www.test.com",
-				Token:                 "abc",
-				Queries:               map[string]string{"test": "test query"},
+				Endpoint: "http://www.test.com",
+				Token:    "abc",
+				ProjectDiscovery: &ProjectDiscovery{
+					Filter:       "^(BE|FE)$",
+					FilterRegexp: regexp.MustCompile("^(BE|FE)$"),
+					Query:        "#Unresolved",
+				},
 				RefreshDelaySeconds:   10,
 				RequestTimeoutSeconds: 10,
+				MaxBodyBytes:          67108864,
+				ResolutionBuckets:     defaultBuckets,
 				ListenPort:            8080,
 			},
 			expectedErr: nil,
 		},
+		{
+			tcase: "invalid project discovery filter",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "project_discovery": {
+    "filter": "^(BE|FE$"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    fmt.Errorf("project discovery: invalid filter: %v", &syntax.Error{Code: syntax.ErrMissingParen, Expr: "^(BE|FE$"}),
+		},
+		{
+			tcase: "reserved discovery query name",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "project_discovery": "#Unresolved"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query project_discovery: name is reserved for project discovery"),
+		},
+		{
+			tcase: "query name collides with discovered project query",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "project_BE": "project: BE #Unresolved"
+  },
+  "project_discovery": {}
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query project_BE: name prefix project_ is reserved for project discovery"),
+		},
+		{
+			tcase: "query template name collides with discovered project query",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "query_templates": {
+    "project": "project: {{.Project}} Priority: Critical"
+  },
+  "project_discovery": {}
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query template project: name is reserved for project discovery"),
+		},
+		{
+			tcase: "empty agile board name",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "agile_boards": [
+    "Backend",
+    {
+      "estimation_field": "Story points"
+    }
+  ]
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("agile board 1: empty name"),
+		},
+		{
+			tcase: "unknown type",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {
+      "type": "comments",
+      "query": "test query"
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: unknown type: comments"),
+		},
+		{
+			tcase: "unknown title mode",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {
+      "query": "test query",
+      "title": "hidden"
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: unknown title mode: hidden"),
+		},
+		{
+			tcase: "negative title max length",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {
+      "query": "test query",
+      "title_max_length": -1
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: negative title max length"),
+		},
+		{
+			tcase: "buckets are not in increasing order",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {
+      "type": "resolution",
+      "query": "test query"
+    }
+  },
+  "resolution_buckets": [60, 3600, 600]
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("resolution buckets are not in increasing order"),
+		},
+		{
+			tcase: "sla for not issues query",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {
+      "type": "count",
+      "query": "test query",
+      "sla": {"threshold_seconds": 7200}
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: sla is not supported for type: count"),
+		},
+		{
+			tcase: "sla without threshold",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {
+      "query": "test query",
+      "sla": {"start_field": "Reopened"}
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: sla: non-positive threshold"),
+		},
+		{
+			tcase: "thresholds for work items query",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {"query": "test query", "type": "work_items", "thresholds": {"warning": {"count": 5}}}
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: thresholds: not supported for type: work_items"),
+		},
+		{
+			tcase: "empty thresholds",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {"query": "test query", "thresholds": {}}
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: thresholds: empty thresholds"),
+		},
+		{
+			tcase: "negative threshold",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {"query": "test query", "thresholds": {"warning": {"count": -1}}}
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: thresholds: warning: negative value"),
+		},
+		{
+			tcase: "empty threshold",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {"query": "test query", "thresholds": {"warning": {"count": 5}, "critical": {}}}
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: thresholds: critical: empty threshold"),
+		},
+		{
+			tcase: "age threshold for count query",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": {"query": "test query", "type": "count", "thresholds": {"critical": {"age_seconds": 3600}}}
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query test: thresholds: critical: age is supported only for type: issues"),
+		},
+		{
+			tcase: "query template unknown type",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "query_templates": {
+    "critical": {
+      "type": "comments",
+      "query": "project: {{.Project}} Priority: Critical",
+      "projects": ["BE"]
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query template critical: unknown type: comments"),
+		},
+		{
+			tcase: "query template parse error",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "query_templates": {
+    "critical": {
+      "query": "project: {{.Project} Priority: Critical",
+      "projects": ["BE"]
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    fmt.Errorf("query template critical: %v", templateParseErr("critical", "project: {{.Project} Priority: Critical")),
+		},
+		{
+			tcase: "query template unknown placeholder",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "query_templates": {
+    "critical": {
+      "query": "project: {{.Team}} Priority: Critical",
+      "projects": ["BE"]
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    fmt.Errorf("query template critical: %v", templateExecuteErr("critical", "project: {{.Team}} Priority: Critical")),
+		},
+		{
+			tcase: "query template without projects",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "query_templates": {
+    "critical": "project: {{.Project}} Priority: Critical"
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query template critical: empty projects and project discovery is disabled"),
+		},
+		{
+			tcase: "query template duplicate query",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "critical_BE": "project: BE Priority: Critical"
+  },
+  "query_templates": {
+    "critical": {
+      "query": "project: {{.Project}} Priority: Critical",
+      "projects": ["BE"]
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query template critical: duplicate query critical_BE"),
+		},
+		{
+			tcase: "query templates duplicate query",
+			raw: []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "query_templates": {
+    "critical_mobile": {
+      "query": "project: {{.Project}} Priority: Critical Subsystem: Mobile",
+      "projects": ["BE"]
+    },
+    "critical": {
+      "query": "project: {{.Project}} Priority: Critical",
+      "projects": ["mobile_BE"]
+    }
+  }
+}`),
+			expectedConfig: nil,
+			expectedErr:    errors.New("query template critical_mobile: duplicate query critical_mobile_BE"),
+		},
 		{
 			tcase:          "invalid json",
 			raw:            []byte(``),
@@ -109,3 +1056,52 @@ func TestNew(t *testing.T) {
 		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
 	}
 }
+
+func TestNew_QueryTemplates(t *testing.T) {
+	t.Parallel()
+
+	raw := []byte(`
+{
+  "endpoint": "http://www.test.com",
+  "token": "abc",
+  "queries": {
+    "test": "test query"
+  },
+  "query_templates": {
+    "critical": {
+      "query": "project: {{.Project}} #Unresolved Priority: Critical",
+      "url_label": true,
+      "projects": ["BE", "FE"]
+    },
+    "unresolved": {
+      "type": "count",
+      "query": "project: {{.Project}} #Unresolved"
+    }
+  },
+  "project_discovery": {}
+}`)
+
+	config, err := New(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Query{
+		"test":        {Type: TypeIssues, Query: "test query", Title: TitleLabel},
+		"critical_BE": {Type: TypeIssues, Query: "project: BE #Unresolved Priority: Critical", Project: "BE", Title: TitleLabel, URLLabel: true},
+		"critical_FE": {Type: TypeIssues, Query: "project: FE #Unresolved Priority: Critical", Project: "FE", Title: TitleLabel, URLLabel: true},
+	}, config.Queries)
+	assert.Equal(t, []string{"BE", "FE"}, config.QueryTemplates["critical"].Projects)
+	assert.Empty(t, config.QueryTemplates["unresolved"].Projects)
+
+	queryName, query, err := config.QueryTemplates["unresolved"].Expand("unresolved", "QA")
+	assert.NoError(t, err)
+	assert.Equal(t, "unresolved_QA", queryName)
+	assert.Equal(t, Query{Type: TypeCount, Query: "project: QA #Unresolved", Project: "QA", Title: TitleLabel}, query)
+}
+
+func templateParseErr(name, text string) error {
+	_, err := template.New(name).Parse(text)
+	return err
+}
+
+func templateExecuteErr(name, text string) error {
+	return template.Must(template.New(name).Parse(text)).Execute(ioutil.Discard, templateData{})
+}
@@ -0,0 +1,115 @@
+package filesource
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileSource reads issues from local JSON or CSV file, query is file path.
+// JSON file contains array of objects with id, title and url fields.
+// CSV file contains header with id, title and url columns, title and url are optional.
+type FileSource struct{}
+
+// New creates FileSource instance.
+func New() *FileSource {
+	return &FileSource{}
+}
+
+// GetIssues reads issues from file with passed path, file format is selected by extension.
+func (s *FileSource) GetIssues(path string) (issues map[string]model.Issue, err error) {
+	var read func(r io.Reader) ([]model.Issue, error)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		read = readJSON
+	case ".csv":
+		read = readCSV
+	default:
+		return nil, fmt.Errorf("unsupported file extension: %v", ext)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("read error: %v, file close error: %v", err, f.Close())
+	}
+
+	issues = make(map[string]model.Issue, len(list))
+	for _, issue := range list {
+		issues[issue.FullID()] = issue
+	}
+
+	return issues, f.Close()
+}
+
+type fileIssue struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+func readJSON(r io.Reader) ([]model.Issue, error) {
+	var list []fileIssue
+	err := json.NewDecoder(r).Decode(&list)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]model.Issue, 0, len(list))
+	for i, fi := range list {
+		if fi.ID == "" {
+			return nil, fmt.Errorf("issue %v: empty id", i)
+		}
+		issues = append(issues, model.Issue{ID: fi.ID, Title: fi.Title, URL: fi.URL})
+	}
+
+	return issues, nil
+}
+
+func readCSV(r io.Reader) ([]model.Issue, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, errors.New("empty header")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns["id"]; !ok {
+		return nil, errors.New("id column not found")
+	}
+
+	value := func(record []string, column string) string {
+		if i, ok := columns[column]; ok {
+			return record[i]
+		}
+		return ""
+	}
+
+	issues := make([]model.Issue, 0, len(records)-1)
+	for i, record := range records[1:] {
+		id := value(record, "id")
+		if id == "" {
+			return nil, fmt.Errorf("line %v: empty id", i+2)
+		}
+		issues = append(issues, model.Issue{ID: id, Title: value(record, "title"), URL: value(record, "url")})
+	}
+
+	return issues, nil
+}
@@ -0,0 +1,139 @@
+package filesource
+
+import (
+	"errors"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFileSource_GetIssues(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase          string
+		path           string
+		expectedIssues map[string]model.Issue
+		expectedErr    error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "json",
+			path:  "testdata/issues.json",
+			expectedIssues: map[string]model.Issue{
+				"OFF-1 First issue":  {ID: "OFF-1", Title: "First issue", URL: "https://tracker.company.com/OFF-1"},
+				"OFF-2 Second issue": {ID: "OFF-2", Title: "Second issue"},
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase: "csv",
+			path:  "testdata/issues.csv",
+			expectedIssues: map[string]model.Issue{
+				"OFF-1 First issue":              {ID: "OFF-1", Title: "First issue", URL: "https://tracker.company.com/OFF-1"},
+				"OFF-2 Second issue, with comma": {ID: "OFF-2", Title: "Second issue, with comma"},
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase:          "csv without issues",
+			path:           "testdata/empty.csv",
+			expectedIssues: map[string]model.Issue{},
+			expectedErr:    nil,
+		},
+		{
+			tcase:          "unsupported extension",
+			path:           "testdata/issues.xml",
+			expectedIssues: nil,
+			expectedErr:    errors.New("unsupported file extension: .xml"),
+		},
+	}
+
+	source := New()
+	for _, testUnit := range testTable {
+		issues, err := source.GetIssues(testUnit.path)
+		assert.Equal(t, testUnit.expectedIssues, issues, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+
+	_, err := source.GetIssues("testdata/absent.json")
+	assert.Error(t, err)
+}
+
+func Test_readJSON(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase          string
+		raw            string
+		expectedIssues []model.Issue
+		expectedErr    error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:          "success",
+			raw:            `[{"id": "OFF-1", "title": "First issue"}]`,
+			expectedIssues: []model.Issue{{ID: "OFF-1", Title: "First issue"}},
+			expectedErr:    nil,
+		},
+		{
+			tcase:          "empty id",
+			raw:            `[{"id": "OFF-1"}, {"title": "Second issue"}]`,
+			expectedIssues: nil,
+			expectedErr:    errors.New("issue 1: empty id"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		issues, err := readJSON(strings.NewReader(testUnit.raw))
+		assert.Equal(t, testUnit.expectedIssues, issues, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func Test_readCSV(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase          string
+		raw            string
+		expectedIssues []model.Issue
+		expectedErr    error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:          "columns in any order and case",
+			raw:            "URL, Id\nhttps://tracker.company.com/OFF-1,OFF-1\n",
+			expectedIssues: []model.Issue{{ID: "OFF-1", URL: "https://tracker.company.com/OFF-1"}},
+			expectedErr:    nil,
+		},
+		{
+			tcase:          "empty file",
+			raw:            "",
+			expectedIssues: nil,
+			expectedErr:    errors.New("empty header"),
+		},
+		{
+			tcase:          "id column not found",
+			raw:            "title\nFirst issue\n",
+			expectedIssues: nil,
+			expectedErr:    errors.New("id column not found"),
+		},
+		{
+			tcase:          "empty id",
+			raw:            "id,title\nOFF-1,First issue\n,Second issue\n",
+			expectedIssues: nil,
+			expectedErr:    errors.New("line 3: empty id"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		issues, err := readCSV(strings.NewReader(testUnit.raw))
+		assert.Equal(t, testUnit.expectedIssues, issues, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
@@ -0,0 +1,115 @@
+package httpwrap
+
+import (
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=breaker.go -destination=breaker_mocks.go -package=httpwrap doc github.com/golang/mock/gomock
+
+// Circuit breaker states, values are exported as metric.
+const (
+	CircuitClosed   = 0
+	CircuitOpen     = 1
+	CircuitHalfOpen = 2
+)
+
+type breakerMetricser interface {
+	SetCircuitState(state int)
+}
+
+// CircuitOpenError is returned when request is short-circuited by open circuit breaker.
+type CircuitOpenError struct {
+	// Until is time when circuit breaker half-opens
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return "circuit breaker is open"
+}
+
+// Breaker is circuit breaker which opens after consecutive failed requests.
+// Open breaker short-circuits requests until cooldown is passed, then it half-opens and lets one probe request through.
+// Successful probe closes breaker and failed one opens it again. Breaker is safe for concurrent use.
+// Only results of requests allowed in current state are recorded, so request started before breaker opened
+// does not change state of half-open breaker.
+type Breaker struct {
+	metricser breakerMetricser
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    int
+	failures int
+	openedAt time.Time
+	probing  bool
+	// generation is changed with state, it is returned by allow and passed to done
+	generation uint64
+}
+
+// NewBreaker creates Breaker instance which opens after threshold consecutive failures.
+func NewBreaker(threshold int, cooldown time.Duration, metricser breakerMetricser) *Breaker {
+	metricser.SetCircuitState(CircuitClosed)
+	return &Breaker{
+		metricser: metricser,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// allow returns CircuitOpenError if request must be short-circuited.
+// Returned generation must be passed to done with request result.
+func (b *Breaker) allow() (generation uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen {
+		until := b.openedAt.Add(b.cooldown)
+		if b.now().Before(until) {
+			return 0, &CircuitOpenError{Until: until}
+		}
+		b.setState(CircuitHalfOpen)
+	}
+
+	if b.state == CircuitHalfOpen {
+		if b.probing {
+			return 0, &CircuitOpenError{Until: b.openedAt.Add(b.cooldown)}
+		}
+		b.probing = true
+	}
+
+	return b.generation, nil
+}
+
+// done records whether request failed, results of requests allowed in previous states are ignored.
+func (b *Breaker) done(generation uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if generation != b.generation {
+		return
+	}
+
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		b.setState(CircuitClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		b.setState(CircuitOpen)
+	}
+}
+
+func (b *Breaker) setState(state int) {
+	if b.state != state {
+		b.state = state
+		b.generation++
+		b.metricser.SetCircuitState(state)
+	}
+}
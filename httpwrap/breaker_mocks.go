@@ -0,0 +1,43 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: breaker.go
+
+// Package httpwrap is a generated GoMock package.
+package httpwrap
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockbreakerMetricser is a mock of breakerMetricser interface
+type MockbreakerMetricser struct {
+	ctrl     *gomock.Controller
+	recorder *MockbreakerMetricserMockRecorder
+}
+
+// MockbreakerMetricserMockRecorder is the mock recorder for MockbreakerMetricser
+type MockbreakerMetricserMockRecorder struct {
+	mock *MockbreakerMetricser
+}
+
+// NewMockbreakerMetricser creates a new mock instance
+func NewMockbreakerMetricser(ctrl *gomock.Controller) *MockbreakerMetricser {
+	mock := &MockbreakerMetricser{ctrl: ctrl}
+	mock.recorder = &MockbreakerMetricserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockbreakerMetricser) EXPECT() *MockbreakerMetricserMockRecorder {
+	return m.recorder
+}
+
+// SetCircuitState mocks base method
+func (m *MockbreakerMetricser) SetCircuitState(state int) {
+	m.ctrl.Call(m, "SetCircuitState", state)
+}
+
+// SetCircuitState indicates an expected call of SetCircuitState
+func (mr *MockbreakerMetricserMockRecorder) SetCircuitState(state interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCircuitState", reflect.TypeOf((*MockbreakerMetricser)(nil).SetCircuitState), state)
+}
@@ -0,0 +1,159 @@
+package httpwrap
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	metricser := NewMockbreakerMetricser(ctrl)
+	metricser.EXPECT().SetCircuitState(CircuitClosed)
+
+	b := NewBreaker(2, time.Minute, metricser)
+	b.now = func() time.Time { return now }
+
+	type testTableData struct {
+		tcase         string
+		advance       time.Duration
+		failed        bool
+		expectFunc    func(m *MockbreakerMetricser)
+		expectedErr   error
+		expectedState int
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:         "success",
+			failed:        false,
+			expectFunc:    func(m *MockbreakerMetricser) {},
+			expectedState: CircuitClosed,
+		},
+		{
+			tcase:         "first failure",
+			failed:        true,
+			expectFunc:    func(m *MockbreakerMetricser) {},
+			expectedState: CircuitClosed,
+		},
+		{
+			tcase:  "threshold failure",
+			failed: true,
+			expectFunc: func(m *MockbreakerMetricser) {
+				m.EXPECT().SetCircuitState(CircuitOpen)
+			},
+			expectedState: CircuitOpen,
+		},
+		{
+			tcase:         "short-circuited",
+			advance:       30 * time.Second,
+			expectFunc:    func(m *MockbreakerMetricser) {},
+			expectedErr:   &CircuitOpenError{Until: time.Date(2019, 1, 1, 0, 1, 0, 0, time.UTC)},
+			expectedState: CircuitOpen,
+		},
+		{
+			tcase:   "failed probe",
+			advance: 30 * time.Second,
+			failed:  true,
+			expectFunc: func(m *MockbreakerMetricser) {
+				m.EXPECT().SetCircuitState(CircuitHalfOpen)
+				m.EXPECT().SetCircuitState(CircuitOpen)
+			},
+			expectedState: CircuitOpen,
+		},
+		{
+			tcase:   "successful probe",
+			advance: time.Minute,
+			failed:  false,
+			expectFunc: func(m *MockbreakerMetricser) {
+				m.EXPECT().SetCircuitState(CircuitHalfOpen)
+				m.EXPECT().SetCircuitState(CircuitClosed)
+			},
+			expectedState: CircuitClosed,
+		},
+	}
+
+	for _, testUnit := range testTable {
+		now = now.Add(testUnit.advance)
+		testUnit.expectFunc(metricser)
+
+		generation, err := b.allow()
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+		if err == nil {
+			b.done(generation, testUnit.failed)
+		}
+		assert.Equal(t, testUnit.expectedState, b.state, testUnit.tcase)
+	}
+}
+
+func TestBreaker_HalfOpenSingleProbe(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricser := NewMockbreakerMetricser(ctrl)
+	metricser.EXPECT().SetCircuitState(CircuitHalfOpen)
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := &Breaker{metricser: metricser, threshold: 1, cooldown: time.Minute, now: func() time.Time { return now }}
+	b.state = CircuitOpen
+	b.openedAt = now.Add(-2 * time.Minute)
+
+	_, err := b.allow()
+	assert.NoError(t, err)
+	_, err = b.allow()
+	assert.Equal(t, &CircuitOpenError{Until: now.Add(-time.Minute)}, err)
+}
+
+func TestBreaker_StaleResult(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	metricser := NewMockbreakerMetricser(ctrl)
+	gomock.InOrder(
+		metricser.EXPECT().SetCircuitState(CircuitClosed),
+		metricser.EXPECT().SetCircuitState(CircuitOpen),
+		metricser.EXPECT().SetCircuitState(CircuitHalfOpen),
+		metricser.EXPECT().SetCircuitState(CircuitClosed),
+	)
+
+	b := NewBreaker(1, time.Minute, metricser)
+	b.now = func() time.Time { return now }
+
+	stale, err := b.allow()
+	assert.NoError(t, err)
+
+	failed, err := b.allow()
+	assert.NoError(t, err)
+	b.done(failed, true)
+	assert.Equal(t, CircuitOpen, b.state)
+
+	now = now.Add(time.Minute)
+	probe, err := b.allow()
+	assert.NoError(t, err)
+
+	// request allowed before breaker opened does not fail probe
+	b.done(stale, true)
+	assert.Equal(t, CircuitHalfOpen, b.state)
+	_, err = b.allow()
+	assert.Equal(t, &CircuitOpenError{Until: now}, err)
+
+	b.done(probe, false)
+	assert.Equal(t, CircuitClosed, b.state)
+}
+
+func TestCircuitOpenError_Error(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "circuit breaker is open", (&CircuitOpenError{}).Error())
+}
@@ -0,0 +1,142 @@
+package httpwrap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=cache.go -destination=cache_mocks.go -package=httpwrap doc github.com/golang/mock/gomock
+
+// ErrNotModified is returned when response body has not changed since the previous request.
+var ErrNotModified = errors.New("not modified")
+
+type cacheMetricser interface {
+	CacheRequestInc(hit bool)
+}
+
+// Cache stores GET responses and makes conditional requests with their ETag and Last-Modified validators.
+// Not modified response is served from cache. Response is a hit if it is not modified or its body hash is the same
+// as cached one (if server does not support conditional requests). Only hash of streamed response body is cached,
+// so streamed response is not kept in memory. Cache is safe for concurrent use.
+type Cache struct {
+	metricser  cacheMetricser
+	maxEntries int
+	maxBytes   int64
+	now        func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	// bytes is total size of cached bodies
+	bytes int64
+}
+
+type cacheEntry struct {
+	etag         string
+	lastModified string
+	// body is nil for streamed response
+	body []byte
+	hash string
+	used time.Time
+}
+
+// NewCache creates Cache instance, the least recently used entries are evicted if cache has maxEntries entries
+// or total size of cached bodies exceeds maxBytes. Body larger than maxBytes is not cached.
+func NewCache(maxEntries int, maxBytes int64, metricser cacheMetricser) *Cache {
+	return &Cache{
+		metricser:  metricser,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		now:        time.Now,
+		entries:    make(map[string]*cacheEntry),
+	}
+}
+
+// prepare sets conditional headers of request from cached response.
+func (c *Cache) prepare(req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[req.URL.String()]
+	if !ok {
+		return
+	}
+
+	if entry.etag != "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+// notModified returns cached body and its hash for not modified response.
+func (c *Cache) notModified(key string) (body []byte, hash string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, "", false
+	}
+
+	entry.used = c.now()
+	c.metricser.CacheRequestInc(true)
+	return entry.body, entry.hash, true
+}
+
+// store caches response body with its hash, body is nil for streamed response.
+func (c *Cache) store(key string, header http.Header, body []byte, hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	c.metricser.CacheRequestInc(ok && entry.hash == hash)
+	if ok {
+		c.remove(key)
+	}
+
+	size := int64(len(body))
+	if size > c.maxBytes {
+		return
+	}
+	for len(c.entries) > 0 && (len(c.entries) >= c.maxEntries || c.bytes+size > c.maxBytes) {
+		c.evict()
+	}
+
+	c.bytes += size
+	c.entries[key] = &cacheEntry{
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+		body:         body,
+		hash:         hash,
+		used:         c.now(),
+	}
+}
+
+// evict removes the least recently used entry.
+func (c *Cache) evict() {
+	var (
+		oldestKey  string
+		oldestUsed time.Time
+	)
+	for key, entry := range c.entries {
+		if oldestKey == "" || entry.used.Before(oldestUsed) {
+			oldestKey, oldestUsed = key, entry.used
+		}
+	}
+	c.remove(oldestKey)
+}
+
+func (c *Cache) remove(key string) {
+	c.bytes -= int64(len(c.entries[key].body))
+	delete(c.entries, key)
+}
+
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cache.go
+
+// Package httpwrap is a generated GoMock package.
+package httpwrap
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockcacheMetricser is a mock of cacheMetricser interface
+type MockcacheMetricser struct {
+	ctrl     *gomock.Controller
+	recorder *MockcacheMetricserMockRecorder
+}
+
+// MockcacheMetricserMockRecorder is the mock recorder for MockcacheMetricser
+type MockcacheMetricserMockRecorder struct {
+	mock *MockcacheMetricser
+}
+
+// NewMockcacheMetricser creates a new mock instance
+func NewMockcacheMetricser(ctrl *gomock.Controller) *MockcacheMetricser {
+	mock := &MockcacheMetricser{ctrl: ctrl}
+	mock.recorder = &MockcacheMetricserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockcacheMetricser) EXPECT() *MockcacheMetricserMockRecorder {
+	return m.recorder
+}
+
+// CacheRequestInc mocks base method
+func (m *MockcacheMetricser) CacheRequestInc(hit bool) {
+	m.ctrl.Call(m, "CacheRequestInc", hit)
+}
+
+// CacheRequestInc indicates an expected call of CacheRequestInc
+func (mr *MockcacheMetricserMockRecorder) CacheRequestInc(hit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CacheRequestInc", reflect.TypeOf((*MockcacheMetricser)(nil).CacheRequestInc), hit)
+}
@@ -0,0 +1,265 @@
+package httpwrap
+
+import (
+	"bytes"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClientWrap_WithCache(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	metricser := NewMockcacheMetricser(ctrl)
+	cache := NewCache(10, 1024, metricser)
+	clientWrap := (&ClientWrap{c: doerMock}).WithCache(cache)
+
+	response := func(status int, header http.Header, body string) *http.Response {
+		return &http.Response{StatusCode: status, Header: header, Body: ioutil.NopCloser(bytes.NewBufferString(body))}
+	}
+
+	type testTableData struct {
+		tcase        string
+		expectFunc   func(d *Mockdoer, m *MockcacheMetricser)
+		expectedBody []byte
+		expectedErr  error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "miss",
+			expectFunc: func(d *Mockdoer, m *MockcacheMetricser) {
+				d.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					assert.Empty(t, req.Header.Get("If-None-Match"))
+					assert.Empty(t, req.Header.Get("If-Modified-Since"))
+				}).Return(response(http.StatusOK, http.Header{
+					"Etag":          {`"v1"`},
+					"Last-Modified": {"Tue, 01 Jan 2019 00:00:00 GMT"},
+				}, "body 1"), nil)
+				m.EXPECT().CacheRequestInc(false)
+			},
+			expectedBody: []byte("body 1"),
+		},
+		{
+			tcase: "not modified",
+			expectFunc: func(d *Mockdoer, m *MockcacheMetricser) {
+				d.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					assert.Equal(t, `"v1"`, req.Header.Get("If-None-Match"))
+					assert.Equal(t, "Tue, 01 Jan 2019 00:00:00 GMT", req.Header.Get("If-Modified-Since"))
+				}).Return(response(http.StatusNotModified, nil, ""), nil)
+				m.EXPECT().CacheRequestInc(true)
+			},
+			expectedBody: []byte("body 1"),
+		},
+		{
+			tcase: "same body without validators",
+			expectFunc: func(d *Mockdoer, m *MockcacheMetricser) {
+				d.EXPECT().Do(gomock.Any()).Return(response(http.StatusOK, nil, "body 1"), nil)
+				m.EXPECT().CacheRequestInc(true)
+			},
+			expectedBody: []byte("body 1"),
+		},
+		{
+			tcase: "changed body",
+			expectFunc: func(d *Mockdoer, m *MockcacheMetricser) {
+				d.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					assert.Empty(t, req.Header.Get("If-None-Match"))
+				}).Return(response(http.StatusOK, nil, "body 2"), nil)
+				m.EXPECT().CacheRequestInc(false)
+			},
+			expectedBody: []byte("body 2"),
+		},
+		{
+			tcase: "error is not cached",
+			expectFunc: func(d *Mockdoer, m *MockcacheMetricser) {
+				d.EXPECT().Do(gomock.Any()).Return(response(http.StatusBadGateway, nil, ""), nil)
+			},
+			expectedErr: &StatusError{StatusCode: http.StatusBadGateway},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(doerMock, metricser)
+		body, err := clientWrap.MakeRequest("http://www.test.com/", nil)
+		assert.Equal(t, testUnit.expectedBody, body, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestClientWrap_WithCache_StreamIfChanged(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	metricser := NewMockcacheMetricser(ctrl)
+	cache := NewCache(10, 1024, metricser)
+	clientWrap := (&ClientWrap{c: doerMock}).WithCache(cache)
+
+	const bodyHash = "ae12b53e4884022320e199e4133840d764f5947682d9f025b1918662eeb7b922"
+
+	gomock.InOrder(
+		doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Etag": {`"v1"`}},
+			Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+		}, nil),
+		metricser.EXPECT().CacheRequestInc(false),
+		doerMock.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+			assert.Equal(t, `"v1"`, req.Header.Get("If-None-Match"))
+		}).Return(&http.Response{
+			StatusCode: http.StatusNotModified,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		}, nil),
+		metricser.EXPECT().CacheRequestInc(true),
+	)
+
+	var reads int
+	read := func(r io.Reader) error {
+		reads++
+		_, err := ioutil.ReadAll(r)
+		return err
+	}
+
+	hash, err := clientWrap.StreamIfChanged(&Request{URL: "http://www.test.com/"}, "", read)
+	assert.Equal(t, bodyHash, hash)
+	assert.NoError(t, err)
+	// only hash of streamed body is cached
+	assert.Nil(t, cache.entries["http://www.test.com/"].body)
+
+	hash, err = clientWrap.StreamIfChanged(&Request{URL: "http://www.test.com/"}, bodyHash, read)
+	assert.Equal(t, bodyHash, hash)
+	assert.Equal(t, ErrNotModified, err)
+	// not modified response is not read
+	assert.Equal(t, 1, reads)
+
+	// body is requested without validators if caller has not got cached one
+	gomock.InOrder(
+		doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusNotModified,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		}, nil),
+		metricser.EXPECT().CacheRequestInc(true),
+		doerMock.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+			assert.Empty(t, req.Header.Get("If-None-Match"))
+		}).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+		}, nil),
+		metricser.EXPECT().CacheRequestInc(true),
+	)
+
+	hash, err = clientWrap.StreamIfChanged(&Request{URL: "http://www.test.com/"}, "", read)
+	assert.Equal(t, bodyHash, hash)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reads)
+}
+
+func TestClientWrap_WithCache_Evicted(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	metricser := NewMockcacheMetricser(ctrl)
+	cache := NewCache(10, 1024, metricser)
+	clientWrap := (&ClientWrap{c: doerMock}).WithCache(cache)
+
+	metricser.EXPECT().CacheRequestInc(false)
+	cache.store("http://www.test.com/", http.Header{"Etag": {`"v1"`}}, []byte("body 1"), bodyHash([]byte("body 1")))
+
+	gomock.InOrder(
+		doerMock.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+			assert.Equal(t, `"v1"`, req.Header.Get("If-None-Match"))
+			// entry is evicted by concurrent request
+			cache.mu.Lock()
+			delete(cache.entries, "http://www.test.com/")
+			cache.mu.Unlock()
+		}).Return(&http.Response{
+			StatusCode: http.StatusNotModified,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+		}, nil),
+		doerMock.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+			assert.Empty(t, req.Header.Get("If-None-Match"))
+		}).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("body 2")),
+		}, nil),
+		metricser.EXPECT().CacheRequestInc(false),
+	)
+
+	body, err := clientWrap.MakeRequest("http://www.test.com/", nil)
+	assert.Equal(t, []byte("body 2"), body)
+	assert.NoError(t, err)
+}
+
+func TestCache_Evict(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricser := NewMockcacheMetricser(ctrl)
+	metricser.EXPECT().CacheRequestInc(false).Times(3)
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(2, 1024, metricser)
+	cache.now = func() time.Time { return now }
+
+	cache.store("a", http.Header{}, []byte("a"), "a")
+	now = now.Add(time.Second)
+	cache.store("b", http.Header{}, []byte("b"), "b")
+	now = now.Add(time.Second)
+	cache.store("c", http.Header{}, []byte("c"), "c")
+
+	assert.Len(t, cache.entries, 2)
+	assert.NotContains(t, cache.entries, "a")
+}
+
+func TestCache_EvictBytes(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricser := NewMockcacheMetricser(ctrl)
+	metricser.EXPECT().CacheRequestInc(false).Times(5)
+	metricser.EXPECT().CacheRequestInc(true)
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := NewCache(10, 8, metricser)
+	cache.now = func() time.Time { return now }
+
+	cache.store("a", http.Header{}, []byte("aaaa"), "a")
+	now = now.Add(time.Second)
+	cache.store("b", http.Header{}, []byte("bbb"), "b")
+	now = now.Add(time.Second)
+	// replaced body is not counted twice
+	cache.store("b", http.Header{}, []byte("bbb"), "b")
+	assert.Equal(t, int64(7), cache.bytes)
+
+	// the least recently used entry is evicted to fit body
+	cache.store("c", http.Header{}, []byte("cc"), "c")
+	assert.Equal(t, int64(5), cache.bytes)
+	assert.NotContains(t, cache.entries, "a")
+
+	// hash of streamed response has no size
+	cache.store("d", http.Header{}, nil, "d")
+	assert.Equal(t, int64(5), cache.bytes)
+	assert.Len(t, cache.entries, 3)
+
+	// too large body is not cached
+	cache.store("e", http.Header{}, []byte("eeeeeeeee"), "e")
+	assert.Equal(t, int64(5), cache.bytes)
+	assert.NotContains(t, cache.entries, "e")
+}
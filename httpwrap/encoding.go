@@ -0,0 +1,65 @@
+package httpwrap
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// acceptEncoding is sent with each request, transport decompression is disabled when it is set explicitly.
+const acceptEncoding = "gzip, deflate"
+
+// decompress replaces response body with decompressing one according to content encoding.
+func decompress(contentEncoding string, body io.ReadCloser) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip":
+		return &decompressedBody{body: body, newReader: func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		}}, nil
+	case "deflate":
+		return &decompressedBody{body: body, newReader: zlib.NewReader}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %v", contentEncoding)
+	}
+}
+
+// decompressedBody creates decompressing reader on first read, so empty bodies are not decompressed.
+type decompressedBody struct {
+	body      io.ReadCloser
+	newReader func(r io.Reader) (io.ReadCloser, error)
+	r         io.ReadCloser
+}
+
+func (d *decompressedBody) Read(p []byte) (int, error) {
+	if d.r == nil {
+		r, err := d.newReader(d.body)
+		if err != nil {
+			return 0, err
+		}
+		d.r = r
+	}
+	return d.r.Read(p)
+}
+
+func (d *decompressedBody) Close() error {
+	if d.r != nil {
+		_ = d.r.Close()
+	}
+	return d.body.Close()
+}
+
+// countingBody counts bytes read from response body.
+type countingBody struct {
+	io.ReadCloser
+	n int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,133 @@
+package httpwrap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func TestClientWrap_MakeRequest_Compression(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	clientWrap := ClientWrap{c: doerMock}
+
+	var gzipped, deflated bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, _ = gw.Write([]byte("resp body"))
+	_ = gw.Close()
+	zw := zlib.NewWriter(&deflated)
+	_, _ = zw.Write([]byte("resp body"))
+	_ = zw.Close()
+
+	type testTableData struct {
+		tcase           string
+		contentEncoding string
+		body            []byte
+		expectedBody    []byte
+		expectedErr     error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:           "identity",
+			contentEncoding: "",
+			body:            []byte("resp body"),
+			expectedBody:    []byte("resp body"),
+			expectedErr:     nil,
+		},
+		{
+			tcase:           "gzip",
+			contentEncoding: "gzip",
+			body:            gzipped.Bytes(),
+			expectedBody:    []byte("resp body"),
+			expectedErr:     nil,
+		},
+		{
+			tcase:           "deflate",
+			contentEncoding: "deflate",
+			body:            deflated.Bytes(),
+			expectedBody:    []byte("resp body"),
+			expectedErr:     nil,
+		},
+		{
+			tcase:           "corrupted gzip",
+			contentEncoding: "gzip",
+			body:            []byte("resp body, not gzipped"),
+			expectedBody:    nil,
+			expectedErr:     errors.New("body read error: gzip: invalid header, body close error: <nil>"),
+		},
+		{
+			tcase:           "unsupported encoding",
+			contentEncoding: "br",
+			body:            []byte("resp body"),
+			expectedBody:    nil,
+			expectedErr:     errors.New("unsupported content encoding: br, body close error: <nil>"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		doerMock.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+			assert.Equal(t, "gzip, deflate", req.Header.Get("Accept-Encoding"))
+		}).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Encoding": {testUnit.contentEncoding}},
+			Body:       ioutil.NopCloser(bytes.NewReader(testUnit.body)),
+		}, nil)
+
+		body, err := clientWrap.MakeRequest("http://www.test.com/", nil)
+		assert.Equal(t, testUnit.expectedBody, body, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestClientWrap_WithMetrics(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	metricser := NewMockrequestMetricser(ctrl)
+	clientWrap := (&ClientWrap{c: doerMock}).WithMetrics(metricser)
+
+	var gzipped bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	_, _ = gw.Write([]byte("resp body"))
+	_ = gw.Close()
+	transferred := int64(gzipped.Len())
+
+	doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Encoding": {"gzip"}},
+		Body:       ioutil.NopCloser(&gzipped),
+	}, nil)
+	metricser.EXPECT().ObserveHTTPRequest("/api/issues", "200", int64(0), transferred, gomock.Any())
+
+	_, err := clientWrap.MakeRequest("http://www.test.com/api/issues?query=x", nil)
+	assert.NoError(t, err)
+
+	doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusUnauthorized,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+	}, nil)
+	metricser.EXPECT().ObserveHTTPRequest("/token", "401", int64(len("grant_type=client_credentials")), int64(0), gomock.Any())
+
+	_, err = clientWrap.PostForm(&Request{URL: "http://www.test.com/token"}, map[string][]string{"grant_type": {"client_credentials"}})
+	assert.Error(t, err)
+
+	doerMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("request error"))
+	metricser.EXPECT().ObserveHTTPRequest("/api/issues", "error", int64(0), int64(0), gomock.Any())
+
+	_, err = clientWrap.MakeRequest("http://www.test.com/api/issues", nil)
+	assert.Error(t, err)
+}
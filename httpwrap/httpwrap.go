@@ -1,20 +1,86 @@
 package httpwrap
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// idPlaceholder replaces entity IDs in path label of request metrics.
+const idPlaceholder = "{id}"
+
 //go:generate mockgen -source=httpwrap.go -destination=httpwrap_mocks.go -package=httpwrap doc github.com/golang/mock/gomock
 
 type doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+type requestMetricser interface {
+	ObserveHTTPRequest(path, code string, requestBytes, responseBytes int64, seconds float64)
+}
+
 // ClientWrap executes HTTP requests.
 type ClientWrap struct {
-	c doer
+	c       doer
+	limiter *Limiter
+	breaker *Breaker
+	cache   *Cache
+	metrics requestMetricser
+	log     *logging.Logger
+	// maxBodySize limits response body size, zero value is not limited
+	maxBodySize int64
+}
+
+// BodyTooLargeError is returned when response body exceeds max body size.
+type BodyTooLargeError struct {
+	Limit int64
+}
+
+func (e *BodyTooLargeError) Error() string {
+	return fmt.Sprintf("response body exceeds limit of %v bytes", e.Limit)
+}
+
+// StatusError is returned when response has not OK HTTP status.
+type StatusError struct {
+	StatusCode   int
+	BodyCloseErr error
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("returned HTTP status: %v, body close error: %v", e.StatusCode, e.BodyCloseErr)
+}
+
+// Request describes HTTP request.
+type Request struct {
+	// Method is GET if empty
+	Method string
+	URL    string
+	// Query parameters are added to URL query
+	Query   url.Values
+	Headers map[string]string
+	// JSON is encoded to request body if set, Body is ignored then
+	JSON interface{}
+	Body io.Reader
+	// Timeout limits request including response body read, zero value is limited by client timeout only
+	Timeout time.Duration
+}
+
+// Response describes HTTP response.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
 }
 
 // New creates wrapper for http.Client for handy making requests.
@@ -22,30 +88,434 @@ func New(client *http.Client) *ClientWrap {
 	return &ClientWrap{c: client}
 }
 
-// MakeRequest making request for passed parameters.
+// WithLimiter returns copy of ClientWrap which requests are limited by passed limiter.
+func (c *ClientWrap) WithLimiter(limiter *Limiter) *ClientWrap {
+	wrap := *c
+	wrap.limiter = limiter
+	return &wrap
+}
+
+// WithBreaker returns copy of ClientWrap which requests are short-circuited by passed circuit breaker.
+func (c *ClientWrap) WithBreaker(breaker *Breaker) *ClientWrap {
+	wrap := *c
+	wrap.breaker = breaker
+	return &wrap
+}
+
+// WithCache returns copy of ClientWrap which GET requests are cached by passed cache.
+func (c *ClientWrap) WithCache(cache *Cache) *ClientWrap {
+	wrap := *c
+	wrap.cache = cache
+	return &wrap
+}
+
+// WithMaxBodySize returns copy of ClientWrap which responses are limited by passed size in bytes.
+func (c *ClientWrap) WithMaxBodySize(size int64) *ClientWrap {
+	wrap := *c
+	wrap.maxBodySize = size
+	return &wrap
+}
+
+// WithMetrics returns copy of ClientWrap which requests are observed by passed metricser.
+func (c *ClientWrap) WithMetrics(metricser requestMetricser) *ClientWrap {
+	wrap := *c
+	wrap.metrics = metricser
+	return &wrap
+}
+
+// Do makes passed request. Response is returned for any status, error is *StatusError if status is not 2xx.
+// GET requests are cached if cache is set, not modified response is returned with cached body and OK status.
+func (c *ClientWrap) Do(r *Request) (*Response, error) {
+	req, cancel, err := newRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+
+	return c.do(req, r.Headers)
+}
+
+// WithLogger returns copy of ClientWrap which requests are logged with debug level.
+func (c *ClientWrap) WithLogger(log *logging.Logger) *ClientWrap {
+	wrap := *c
+	wrap.log = log
+	return &wrap
+}
+
+// MakeRequest making GET request for passed parameters.
 func (c *ClientWrap) MakeRequest(url string, headers map[string]string) ([]byte, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	resp, err := c.Do(&Request{URL: url, Headers: headers})
 	if err != nil {
 		return nil, err
 	}
 
+	return resp.Body, nil
+}
+
+// StreamIfChanged makes passed request and passes response body to read, body is hashed while it is read.
+// Returns ErrNotModified after read if body hash is equal to passed one, so result of read may be dropped.
+// GET requests are conditional if cache is set, not modified response is not read.
+func (c *ClientWrap) StreamIfChanged(r *Request, hash string, read func(body io.Reader) error) (string, error) {
+	req, cancel, err := newRequest(r)
+	if err != nil {
+		return "", err
+	}
+	defer cancel()
+
+	var (
+		newHash string
+		cached  = c.cache != nil && req.Method == "GET"
+	)
+
+	readBody := func(resp *http.Response) error {
+		if !successful(resp.StatusCode) {
+			return &StatusError{StatusCode: resp.StatusCode}
+		}
+
+		hasher := sha256.New()
+		body := io.TeeReader(c.limitBody(resp.Body), hasher)
+		err := read(body)
+		if err != nil {
+			return err
+		}
+		// the rest of body which is not consumed by read is hashed too
+		_, err = io.Copy(ioutil.Discard, body)
+		if err != nil {
+			return err
+		}
+
+		newHash = hex.EncodeToString(hasher.Sum(nil))
+		if cached {
+			c.cache.store(req.URL.String(), resp.Header, nil, newHash)
+		}
+		return nil
+	}
+
+	if cached {
+		err = c.sendCached(req, r.Headers, func(*http.Response) bool {
+			_, cachedHash, ok := c.cache.notModified(req.URL.String())
+			if ok && cachedHash == hash {
+				newHash = hash
+				return true
+			}
+			return false
+		}, readBody)
+	} else {
+		err = c.send(req, r.Headers, readBody)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if newHash == hash {
+		return hash, ErrNotModified
+	}
+	return newHash, nil
+}
+
+// Stream makes passed request and passes response body to read,
+// so body may be decoded incrementally without reading it into memory. Responses are not cached.
+func (c *ClientWrap) Stream(r *Request, read func(body io.Reader) error) error {
+	req, cancel, err := newRequest(r)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return c.send(req, r.Headers, func(resp *http.Response) error {
+		if !successful(resp.StatusCode) {
+			return &StatusError{StatusCode: resp.StatusCode}
+		}
+		return read(c.limitBody(resp.Body))
+	})
+}
+
+// PostForm makes passed request as POST request with URL encoded form body.
+func (c *ClientWrap) PostForm(r *Request, form url.Values) ([]byte, error) {
+	formHeaders := map[string]string{"Content-Type": "application/x-www-form-urlencoded"}
+	for key, val := range r.Headers {
+		formHeaders[key] = val
+	}
+
+	req := *r
+	req.Method = "POST"
+	req.Headers = formHeaders
+	req.Body = strings.NewReader(form.Encode())
+	resp, err := c.Do(&req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// newRequest creates HTTP request with query parameters, body and timeout of passed request.
+// Returned cancel func must be called when request is done.
+func newRequest(r *Request) (*http.Request, context.CancelFunc, error) {
+	method := r.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(r.Query) > 0 {
+		query := u.Query()
+		for key, vals := range r.Query {
+			for _, val := range vals {
+				query.Add(key, val)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	body := r.Body
+	if r.JSON != nil {
+		encoded, err := json.Marshal(r.JSON)
+		if err != nil {
+			return nil, nil, fmt.Errorf("request body encode error: %v", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if r.JSON != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if r.Timeout <= 0 {
+		return req, func() {}, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout)
+	return req.WithContext(ctx), cancel, nil
+}
+
+func (c *ClientWrap) do(req *http.Request, headers map[string]string) (*Response, error) {
+	var (
+		response *Response
+		cached   = c.cache != nil && req.Method == "GET"
+	)
+
+	read := func(resp *http.Response) error {
+		body, err := ioutil.ReadAll(c.limitBody(resp.Body))
+		if !successful(resp.StatusCode) {
+			// body of unsuccessful response is informational, so its read error is ignored
+			response = &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+			return &StatusError{StatusCode: resp.StatusCode}
+		}
+		if err != nil {
+			return err
+		}
+
+		response = &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}
+		if cached {
+			c.cache.store(req.URL.String(), resp.Header, body, bodyHash(body))
+		}
+		return nil
+	}
+
+	if !cached {
+		return response, c.send(req, headers, read)
+	}
+
+	err := c.sendCached(req, headers, func(resp *http.Response) bool {
+		cachedBody, _, ok := c.cache.notModified(req.URL.String())
+		if ok {
+			response = &Response{StatusCode: http.StatusOK, Header: resp.Header, Body: cachedBody}
+		}
+		return ok
+	}, read)
+	return response, err
+}
+
+// sendCached makes request conditional with validators of cached response. Not modified response is passed
+// to notModified which reports whether it is served from cache. Otherwise it is a cache miss, e.g. cached response
+// is evicted after request is prepared, and request is made again without validators.
+func (c *ClientWrap) sendCached(req *http.Request, headers map[string]string, notModified func(resp *http.Response) bool, read func(resp *http.Response) error) error {
+	c.cache.prepare(req)
+	conditional := req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+
+	var miss bool
+	err := c.send(req, headers, func(resp *http.Response) error {
+		if conditional && resp.StatusCode == http.StatusNotModified {
+			miss = !notModified(resp)
+			return nil
+		}
+		return read(resp)
+	})
+	if err != nil || !miss {
+		return err
+	}
+
+	c.log.Debug("not modified response is not cached, retrying without validators", "path", req.URL.Path)
+	req.Header.Del("If-None-Match")
+	req.Header.Del("If-Modified-Since")
+	return c.send(req, headers, read)
+}
+
+// send makes request with passed headers and passes response to read.
+func (c *ClientWrap) send(req *http.Request, headers map[string]string, read func(resp *http.Response) error) error {
+	req.Header.Set("Accept-Encoding", acceptEncoding)
 	for key, val := range headers {
 		req.Header.Set(key, val)
 	}
 
-	resp, err := c.c.Do(req)
+	// Limiter is waited before circuit breaker, so waiting canceled by request context is not a failure of YouTrack
+	if c.limiter != nil {
+		release, err := c.limiter.Wait(req.Context())
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	if c.breaker == nil {
+		return c.roundTrip(req, read)
+	}
+
+	generation, err := c.breaker.allow()
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	// Result is recorded before body is read, so read errors like invalid or too large body are not failures:
+	// only network errors and 5xx statuses mean YouTrack is unavailable
+	recorded := false
+	err = c.roundTrip(req, func(resp *http.Response) error {
+		recorded = true
+		c.breaker.done(generation, resp.StatusCode >= http.StatusInternalServerError)
+		return read(resp)
+	})
+	if !recorded {
+		c.breaker.done(generation, true)
 	}
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("returned HTTP status: %v, body close error: %v", resp.StatusCode, resp.Body.Close())
+// roundTrip makes request and passes response to read, response body is closed after read.
+func (c *ClientWrap) roundTrip(req *http.Request, read func(resp *http.Response) error) error {
+	start := time.Now()
+	resp, err := c.c.Do(req)
+	if err != nil {
+		c.observe(req, "error", 0, start)
+		return err
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	// transferred bytes are counted before decompression
+	transferred := &countingBody{ReadCloser: resp.Body}
+	defer func() {
+		c.observe(req, strconv.Itoa(resp.StatusCode), transferred.n, start)
+	}()
+
+	resp.Body, err = decompress(resp.Header.Get("Content-Encoding"), transferred)
 	if err != nil {
-		return nil, fmt.Errorf("body read error: %v, body close error: %v", err.Error(), resp.Body.Close())
+		return fmt.Errorf("%v, body close error: %v", err.Error(), transferred.Close())
 	}
 
-	return body, resp.Body.Close()
+	err = read(resp)
+	closeErr := resp.Body.Close()
+
+	switch e := err.(type) {
+	case nil:
+		return closeErr
+	case *StatusError:
+		e.BodyCloseErr = closeErr
+		return e
+	case *BodyTooLargeError:
+		return e
+	default:
+		return fmt.Errorf("body read error: %v, body close error: %v", err.Error(), closeErr)
+	}
+}
+
+// successful reports whether status is 2xx.
+func successful(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
+
+// observe exports metrics and logs finished request, headers are logged with redacted authorization.
+func (c *ClientWrap) observe(req *http.Request, code string, responseBytes int64, start time.Time) {
+	duration := time.Since(start)
+	c.log.Debug("http request",
+		"method", req.Method,
+		"host", req.URL.Host,
+		"path", req.URL.Path,
+		"code", code,
+		"response_bytes", responseBytes,
+		"duration", duration,
+		"headers", req.Header,
+	)
+
+	if c.metrics == nil {
+		return
+	}
+
+	var requestBytes int64
+	if req.ContentLength > 0 {
+		requestBytes = req.ContentLength
+	}
+	c.metrics.ObserveHTTPRequest(routePath(req.URL.Path), code, requestBytes, responseBytes, duration.Seconds())
+}
+
+// routePath replaces entity IDs in URL path with placeholder, so path label of metrics is route template.
+func routePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isEntityID(segment) {
+			segments[i] = idPlaceholder
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isEntityID reports whether path segment is YouTrack entity ID like 108-4.
+func isEntityID(segment string) bool {
+	if segment == "" || segment[0] < '0' || segment[0] > '9' {
+		return false
+	}
+	for _, r := range segment {
+		if (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// limitBody limits response body by max body size.
+func (c *ClientWrap) limitBody(body io.Reader) io.Reader {
+	if c.maxBodySize <= 0 {
+		return body
+	}
+	return &limitedReader{r: body, limit: c.maxBodySize, n: c.maxBodySize}
+}
+
+// limitedReader returns BodyTooLargeError if more than limit bytes are read.
+type limitedReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, &BodyTooLargeError{Limit: l.limit}
+	}
+
+	// one byte over limit is read to detect too large body
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n + int(l.n), &BodyTooLargeError{Limit: l.limit}
+	}
+	return n, err
 }
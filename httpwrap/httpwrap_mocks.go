@@ -45,3 +45,36 @@ func (m *Mockdoer) Do(req *http.Request) (*http.Response, error) {
 func (mr *MockdoerMockRecorder) Do(req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*Mockdoer)(nil).Do), req)
 }
+
+// MockrequestMetricser is a mock of requestMetricser interface
+type MockrequestMetricser struct {
+	ctrl     *gomock.Controller
+	recorder *MockrequestMetricserMockRecorder
+}
+
+// MockrequestMetricserMockRecorder is the mock recorder for MockrequestMetricser
+type MockrequestMetricserMockRecorder struct {
+	mock *MockrequestMetricser
+}
+
+// NewMockrequestMetricser creates a new mock instance
+func NewMockrequestMetricser(ctrl *gomock.Controller) *MockrequestMetricser {
+	mock := &MockrequestMetricser{ctrl: ctrl}
+	mock.recorder = &MockrequestMetricserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockrequestMetricser) EXPECT() *MockrequestMetricserMockRecorder {
+	return m.recorder
+}
+
+// ObserveHTTPRequest mocks base method
+func (m *MockrequestMetricser) ObserveHTTPRequest(path, code string, requestBytes, responseBytes int64, seconds float64) {
+	m.ctrl.Call(m, "ObserveHTTPRequest", path, code, requestBytes, responseBytes, seconds)
+}
+
+// ObserveHTTPRequest indicates an expected call of ObserveHTTPRequest
+func (mr *MockrequestMetricserMockRecorder) ObserveHTTPRequest(path, code, requestBytes, responseBytes, seconds interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveHTTPRequest", reflect.TypeOf((*MockrequestMetricser)(nil).ObserveHTTPRequest), path, code, requestBytes, responseBytes, seconds)
+}
@@ -2,9 +2,12 @@ package httpwrap
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
@@ -57,6 +60,7 @@ func TestClientWrap_MakeRequest(t *testing.T) {
 			headers: map[string]string{"Authorization": "123"},
 			expectFunc: func(d *Mockdoer) {
 				req, _ := http.NewRequest("GET", "http://www.test.com/", nil)
+				req.Header.Set("Accept-Encoding", "gzip, deflate")
 				req.Header.Set("Authorization", "123")
 				d.EXPECT().Do(req).Return(&http.Response{
 					StatusCode: http.StatusOK,
@@ -80,6 +84,7 @@ func TestClientWrap_MakeRequest(t *testing.T) {
 			headers: nil,
 			expectFunc: func(d *Mockdoer) {
 				req, _ := http.NewRequest("GET", "http://www.test.com/", nil)
+				req.Header.Set("Accept-Encoding", "gzip, deflate")
 				d.EXPECT().Do(req).Return(nil, errors.New("request error"))
 			},
 			expectedBody: nil,
@@ -91,13 +96,14 @@ func TestClientWrap_MakeRequest(t *testing.T) {
 			headers: nil,
 			expectFunc: func(d *Mockdoer) {
 				req, _ := http.NewRequest("GET", "http://www.test.com/", nil)
+				req.Header.Set("Accept-Encoding", "gzip, deflate")
 				d.EXPECT().Do(req).Return(&http.Response{
 					StatusCode: http.StatusBadGateway,
 					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
 				}, nil)
 			},
 			expectedBody: nil,
-			expectedErr:  errors.New("returned HTTP status: 502, body close error: <nil>"),
+			expectedErr:  &StatusError{StatusCode: http.StatusBadGateway},
 		},
 		{
 			tcase:   "body read error",
@@ -105,6 +111,7 @@ func TestClientWrap_MakeRequest(t *testing.T) {
 			headers: nil,
 			expectFunc: func(d *Mockdoer) {
 				req, _ := http.NewRequest("GET", "http://www.test.com/", nil)
+				req.Header.Set("Accept-Encoding", "gzip, deflate")
 				d.EXPECT().Do(req).Return(&http.Response{
 					StatusCode: http.StatusOK,
 					Body:       ioutil.NopCloser(errorReader{}),
@@ -123,8 +130,738 @@ func TestClientWrap_MakeRequest(t *testing.T) {
 	}
 }
 
+func TestClientWrap_PostForm(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	clientWrap := ClientWrap{c: doerMock}
+
+	type testTableData struct {
+		tcase        string
+		url          string
+		expectFunc   func(d *Mockdoer)
+		expectedBody []byte
+		expectedErr  error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "success request",
+			url:   "http://www.test.com/token",
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					assert.Equal(t, "POST", req.Method)
+					assert.Equal(t, "http://www.test.com/token", req.URL.String())
+					assert.Equal(t, "application/x-www-form-urlencoded", req.Header.Get("Content-Type"))
+					assert.Equal(t, "Basic abc", req.Header.Get("Authorization"))
+					body, err := ioutil.ReadAll(req.Body)
+					assert.NoError(t, err)
+					assert.Equal(t, "grant_type=client_credentials&scope=youtrack", string(body))
+				}).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedBody: []byte("resp body"),
+			expectedErr:  nil,
+		},
+		{
+			tcase:        "bad request url",
+			url:          "http://www test com/",
+			expectFunc:   func(d *Mockdoer) {},
+			expectedBody: nil,
+			expectedErr:  &url.Error{Op: "parse", URL: "http://www test com/", Err: url.InvalidHostError(" ")},
+		},
+		{
+			tcase: "unauthorized",
+			url:   "http://www.test.com/token",
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedBody: nil,
+			expectedErr:  &StatusError{StatusCode: http.StatusUnauthorized},
+		},
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}, "scope": {"youtrack"}}
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(doerMock)
+		body, err := clientWrap.PostForm(&Request{URL: testUnit.url, Headers: map[string]string{"Authorization": "Basic abc"}}, form)
+		assert.Equal(t, testUnit.expectedBody, body, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestClientWrap_Do(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	clientWrap := ClientWrap{c: doerMock}
+
+	type testTableData struct {
+		tcase            string
+		request          *Request
+		expectFunc       func(d *Mockdoer)
+		expectedResponse *Response
+		expectedErr      error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "json post with query params and timeout",
+			request: &Request{
+				Method:  "POST",
+				URL:     "http://www.test.com/api/issuesGetter/count?fields=count",
+				Query:   url.Values{"top": {"10"}},
+				Headers: map[string]string{"Authorization": "123"},
+				JSON:    map[string]string{"query": "#Unresolved"},
+				Timeout: time.Minute,
+			},
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					assert.Equal(t, "POST", req.Method)
+					assert.Equal(t, "http://www.test.com/api/issuesGetter/count?fields=count&top=10", req.URL.String())
+					assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+					assert.Equal(t, "123", req.Header.Get("Authorization"))
+					_, ok := req.Context().Deadline()
+					assert.True(t, ok)
+					body, err := ioutil.ReadAll(req.Body)
+					assert.NoError(t, err)
+					assert.Equal(t, `{"query":"#Unresolved"}`, string(body))
+				}).Return(&http.Response{
+					StatusCode: http.StatusCreated,
+					Header:     http.Header{"X-Test": {"test"}},
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"count":10}`)),
+				}, nil)
+			},
+			expectedResponse: &Response{
+				StatusCode: http.StatusCreated,
+				Header:     http.Header{"X-Test": {"test"}},
+				Body:       []byte(`{"count":10}`),
+			},
+			expectedErr: nil,
+		},
+		{
+			tcase:   "get by default without timeout",
+			request: &Request{URL: "http://www.test.com/"},
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					assert.Equal(t, "GET", req.Method)
+					assert.Equal(t, "http://www.test.com/", req.URL.String())
+					_, ok := req.Context().Deadline()
+					assert.False(t, ok)
+				}).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedResponse: &Response{StatusCode: http.StatusOK, Body: []byte("resp body")},
+			expectedErr:      nil,
+		},
+		{
+			tcase:   "bad status code",
+			request: &Request{URL: "http://www.test.com/"},
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error":"invalid_query"}`)),
+				}, nil)
+			},
+			expectedResponse: &Response{StatusCode: http.StatusBadRequest, Body: []byte(`{"error":"invalid_query"}`)},
+			expectedErr:      &StatusError{StatusCode: http.StatusBadRequest},
+		},
+		{
+			tcase:            "json encode error",
+			request:          &Request{Method: "POST", URL: "http://www.test.com/", JSON: func() {}},
+			expectFunc:       func(d *Mockdoer) {},
+			expectedResponse: nil,
+			expectedErr:      errors.New("request body encode error: json: unsupported type: func()"),
+		},
+		{
+			tcase:            "bad method",
+			request:          &Request{Method: "BAD METHOD", URL: "http://www.test.com/"},
+			expectFunc:       func(d *Mockdoer) {},
+			expectedResponse: nil,
+			expectedErr:      errors.New(`net/http: invalid method "BAD METHOD"`),
+		},
+		{
+			tcase:   "request error",
+			request: &Request{URL: "http://www.test.com/"},
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(nil, errors.New("request error"))
+			},
+			expectedResponse: nil,
+			expectedErr:      errors.New("request error"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(doerMock)
+		response, err := clientWrap.Do(testUnit.request)
+		assert.Equal(t, testUnit.expectedResponse, response, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestClientWrap_WithLogger(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	buf := &bytes.Buffer{}
+	log, err := logging.New(buf, logging.FormatLogfmt, logging.LevelDebug)
+	assert.NoError(t, err)
+
+	doerMock := NewMockdoer(ctrl)
+	clientWrap := (&ClientWrap{c: doerMock}).WithLogger(log)
+
+	doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+	}, nil)
+
+	_, err = clientWrap.MakeRequest("http://www.test.com/api/issues", map[string]string{"Authorization": "Bearer abc"})
+	assert.NoError(t, err)
+
+	logged := buf.String()
+	assert.Contains(t, logged, `level=debug msg="http request" method=GET host=www.test.com path=/api/issues code=200 response_bytes=9`)
+	assert.Contains(t, logged, "Authorization:[[REDACTED]]")
+	assert.NotContains(t, logged, "Bearer abc")
+}
+
+func TestStatusError_Error(t *testing.T) {
+	t.Parallel()
+
+	err := &StatusError{StatusCode: http.StatusBadGateway, BodyCloseErr: errors.New("close error")}
+	assert.Equal(t, "returned HTTP status: 502, body close error: close error", err.Error())
+}
+
 type errorReader struct{}
 
 func (errorReader) Read(p []byte) (n int, err error) {
 	return 0, errors.New("read error")
 }
+
+func TestClientWrap_WithLimiter(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	metricser := NewMocklimiterMetricser(ctrl)
+	limiter := NewLimiter(0, 0, 1, metricser)
+
+	clientWrap := New(nil)
+	clientWrap.c = doerMock
+	limited := clientWrap.WithLimiter(limiter)
+	assert.Equal(t, &ClientWrap{c: doerMock, limiter: limiter}, limited)
+	assert.Nil(t, clientWrap.limiter)
+
+	gomock.InOrder(
+		metricser.EXPECT().AddQueuedRequests(1),
+		metricser.EXPECT().ObserveRequestWait(float64(0)),
+		metricser.EXPECT().AddQueuedRequests(-1),
+		doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+		}, nil),
+	)
+
+	body, err := limited.MakeRequest("http://www.test.com/", nil)
+	assert.Equal(t, []byte("resp body"), body)
+	assert.NoError(t, err)
+	// concurrency slot is released after request
+	assert.Len(t, limiter.slots, 0)
+
+	// request is not made if its timeout is exceeded while waiting for limiter
+	limiter.slots <- struct{}{}
+	metricser.EXPECT().AddQueuedRequests(1)
+	metricser.EXPECT().AddQueuedRequests(-1)
+
+	resp, err := limited.Do(&Request{URL: "http://www.test.com/", Timeout: 10 * time.Millisecond})
+	assert.Nil(t, resp)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestClientWrap_WithBreaker(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	metricser := NewMockbreakerMetricser(ctrl)
+	metricser.EXPECT().SetCircuitState(CircuitClosed)
+	breaker := NewBreaker(1, time.Minute, metricser)
+
+	clientWrap := (&ClientWrap{c: doerMock}).WithBreaker(breaker)
+	assert.Equal(t, &ClientWrap{c: doerMock, breaker: breaker}, clientWrap)
+
+	gomock.InOrder(
+		doerMock.EXPECT().Do(gomock.Any()).Return(nil, errors.New("request error")),
+		metricser.EXPECT().SetCircuitState(CircuitOpen),
+	)
+
+	body, err := clientWrap.MakeRequest("http://www.test.com/", nil)
+	assert.Nil(t, body)
+	assert.Equal(t, errors.New("request error"), err)
+
+	// request is short-circuited without calling doer
+	body, err = clientWrap.MakeRequest("http://www.test.com/", nil)
+	assert.Nil(t, body)
+	assert.IsType(t, &CircuitOpenError{}, err)
+}
+
+func TestClientWrap_WithBreaker_ReadError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	metricser := NewMockbreakerMetricser(ctrl)
+	metricser.EXPECT().SetCircuitState(CircuitClosed)
+	breaker := NewBreaker(1, time.Minute, metricser)
+	clientWrap := (&ClientWrap{c: doerMock, maxBodySize: 4}).WithBreaker(breaker)
+
+	response := func(statusCode int, body string) *http.Response {
+		return &http.Response{StatusCode: statusCode, Body: ioutil.NopCloser(bytes.NewBufferString(body))}
+	}
+	decode := func(body io.Reader) error {
+		_, err := ioutil.ReadAll(body)
+		if err != nil {
+			return err
+		}
+		return errors.New("decode error")
+	}
+
+	type testTableData struct {
+		tcase         string
+		resp          *http.Response
+		expectFunc    func(m *MockbreakerMetricser)
+		expectedErr   error
+		expectedState int
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:         "decode error",
+			resp:          response(http.StatusOK, "[]"),
+			expectFunc:    func(m *MockbreakerMetricser) {},
+			expectedErr:   errors.New("body read error: decode error, body close error: <nil>"),
+			expectedState: CircuitClosed,
+		},
+		{
+			tcase:         "too large body",
+			resp:          response(http.StatusOK, "[{}, {}]"),
+			expectFunc:    func(m *MockbreakerMetricser) {},
+			expectedErr:   &BodyTooLargeError{Limit: 4},
+			expectedState: CircuitClosed,
+		},
+		{
+			tcase:         "client error",
+			resp:          response(http.StatusNotFound, ""),
+			expectFunc:    func(m *MockbreakerMetricser) {},
+			expectedErr:   &StatusError{StatusCode: http.StatusNotFound},
+			expectedState: CircuitClosed,
+		},
+		{
+			tcase: "server error",
+			resp:  response(http.StatusBadGateway, ""),
+			expectFunc: func(m *MockbreakerMetricser) {
+				m.EXPECT().SetCircuitState(CircuitOpen)
+			},
+			expectedErr:   &StatusError{StatusCode: http.StatusBadGateway},
+			expectedState: CircuitOpen,
+		},
+	}
+
+	for _, testUnit := range testTable {
+		doerMock.EXPECT().Do(gomock.Any()).Return(testUnit.resp, nil)
+		testUnit.expectFunc(metricser)
+
+		err := clientWrap.Stream(&Request{URL: "http://www.test.com/"}, decode)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedState, breaker.state, testUnit.tcase)
+	}
+}
+
+func TestClientWrap_StreamIfChanged(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	clientWrap := ClientWrap{c: doerMock}
+
+	const bodyHash = "ae12b53e4884022320e199e4133840d764f5947682d9f025b1918662eeb7b922"
+
+	type testTableData struct {
+		tcase        string
+		hash         string
+		readLen      int
+		readErr      error
+		expectFunc   func(d *Mockdoer)
+		expectedBody string
+		expectedHash string
+		expectedErr  error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:   "changed",
+			hash:    "",
+			readLen: 9,
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedBody: "resp body",
+			expectedHash: bodyHash,
+			expectedErr:  nil,
+		},
+		{
+			tcase:   "not changed",
+			hash:    bodyHash,
+			readLen: 9,
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedBody: "resp body",
+			expectedHash: bodyHash,
+			expectedErr:  ErrNotModified,
+		},
+		{
+			tcase:   "body is not read to the end",
+			hash:    bodyHash,
+			readLen: 4,
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedBody: "resp",
+			expectedHash: bodyHash,
+			expectedErr:  ErrNotModified,
+		},
+		{
+			tcase:   "read error",
+			hash:    bodyHash,
+			readLen: 9,
+			readErr: errors.New("decode error"),
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedBody: "resp body",
+			expectedHash: "",
+			expectedErr:  errors.New("body read error: decode error, body close error: <nil>"),
+		},
+		{
+			tcase: "bad status code",
+			hash:  bodyHash,
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusBadGateway,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedHash: "",
+			expectedErr:  &StatusError{StatusCode: http.StatusBadGateway},
+		},
+		{
+			tcase: "request error",
+			hash:  bodyHash,
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(nil, errors.New("request error"))
+			},
+			expectedHash: "",
+			expectedErr:  errors.New("request error"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(doerMock)
+		var body []byte
+		hash, err := clientWrap.StreamIfChanged(&Request{URL: "http://www.test.com/"}, testUnit.hash, func(r io.Reader) error {
+			body = make([]byte, testUnit.readLen)
+			_, err := io.ReadFull(r, body)
+			assert.NoError(t, err, testUnit.tcase)
+			return testUnit.readErr
+		})
+		assert.Equal(t, testUnit.expectedBody, string(body), testUnit.tcase)
+		assert.Equal(t, testUnit.expectedHash, hash, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestClientWrap_StreamIfChanged_NotBuffered(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	pr, pw := io.Pipe()
+	firstRead := make(chan struct{})
+	go func() {
+		_, _ = pw.Write([]byte("resp "))
+		// the rest of body is written only after the first part is read, so buffered body is never complete
+		select {
+		case <-firstRead:
+			_, _ = pw.Write([]byte("body"))
+			_ = pw.Close()
+		case <-time.After(time.Second):
+			_ = pw.CloseWithError(errors.New("body is buffered"))
+		}
+	}()
+
+	doerMock := NewMockdoer(ctrl)
+	doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{StatusCode: http.StatusOK, Body: pr}, nil)
+
+	clientWrap := ClientWrap{c: doerMock}
+	var body []byte
+	_, err := clientWrap.StreamIfChanged(&Request{URL: "http://www.test.com/"}, "", func(r io.Reader) error {
+		first := make([]byte, 5)
+		_, err := io.ReadFull(r, first)
+		if err != nil {
+			return err
+		}
+		close(firstRead)
+
+		rest, err := ioutil.ReadAll(r)
+		body = append(first, rest...)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "resp body", string(body))
+}
+
+func TestClientWrap_Stream(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+	clientWrap := (&ClientWrap{c: doerMock}).WithMaxBodySize(9)
+
+	type testTableData struct {
+		tcase        string
+		url          string
+		expectFunc   func(d *Mockdoer)
+		expectedBody string
+		expectedErr  error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "success request",
+			url:   "http://www.test.com/",
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Do(func(req *http.Request) {
+					assert.Equal(t, "Bearer abc", req.Header.Get("Authorization"))
+					_, ok := req.Context().Deadline()
+					assert.True(t, ok)
+				}).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedBody: "resp body",
+			expectedErr:  nil,
+		},
+		{
+			tcase:       "bad request url",
+			url:         "http://www test com/",
+			expectFunc:  func(d *Mockdoer) {},
+			expectedErr: &url.Error{Op: "parse", URL: "http://www test com/", Err: url.InvalidHostError(" ")},
+		},
+		{
+			tcase: "bad status code",
+			url:   "http://www.test.com/",
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusBadGateway,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+				}, nil)
+			},
+			expectedErr: &StatusError{StatusCode: http.StatusBadGateway},
+		},
+		{
+			tcase: "body too large",
+			url:   "http://www.test.com/",
+			expectFunc: func(d *Mockdoer) {
+				d.EXPECT().Do(gomock.Any()).Return(&http.Response{
+					StatusCode: http.StatusOK,
+					Body:       ioutil.NopCloser(bytes.NewBufferString("large resp body")),
+				}, nil)
+			},
+			expectedBody: "large res",
+			expectedErr:  &BodyTooLargeError{Limit: 9},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(doerMock)
+		var body []byte
+		r := &Request{URL: testUnit.url, Headers: map[string]string{"Authorization": "Bearer abc"}, Timeout: time.Minute}
+		err := clientWrap.Stream(r, func(r io.Reader) (err error) {
+			body, err = ioutil.ReadAll(r)
+			return err
+		})
+		assert.Equal(t, testUnit.expectedBody, string(body), testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestClientWrap_MakeRequest_MaxBodySize(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	doerMock := NewMockdoer(ctrl)
+
+	type testTableData struct {
+		tcase        string
+		maxBodySize  int64
+		expectedBody []byte
+		expectedErr  error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:        "not limited",
+			maxBodySize:  0,
+			expectedBody: []byte("resp body"),
+			expectedErr:  nil,
+		},
+		{
+			tcase:        "body size equals limit",
+			maxBodySize:  9,
+			expectedBody: []byte("resp body"),
+			expectedErr:  nil,
+		},
+		{
+			tcase:        "body too large",
+			maxBodySize:  8,
+			expectedBody: nil,
+			expectedErr:  &BodyTooLargeError{Limit: 8},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		doerMock.EXPECT().Do(gomock.Any()).Return(&http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("resp body")),
+		}, nil)
+
+		clientWrap := (&ClientWrap{c: doerMock}).WithMaxBodySize(testUnit.maxBodySize)
+		body, err := clientWrap.MakeRequest("http://www.test.com/", nil)
+		assert.Equal(t, testUnit.expectedBody, body, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestLimitedReader(t *testing.T) {
+	t.Parallel()
+
+	r := &limitedReader{r: bytes.NewBufferString("resp body"), limit: 4, n: 4}
+
+	type testTableData struct {
+		tcase       string
+		bufLen      int
+		expectedN   int
+		expectedErr error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:       "within limit",
+			bufLen:      3,
+			expectedN:   3,
+			expectedErr: nil,
+		},
+		{
+			tcase:       "over limit",
+			bufLen:      3,
+			expectedN:   1,
+			expectedErr: &BodyTooLargeError{Limit: 4},
+		},
+		{
+			tcase:       "after limit",
+			bufLen:      3,
+			expectedN:   0,
+			expectedErr: &BodyTooLargeError{Limit: 4},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		n, err := r.Read(make([]byte, testUnit.bufLen))
+		assert.Equal(t, testUnit.expectedN, n, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+	}
+}
+
+func TestBodyTooLargeError_Error(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "response body exceeds limit of 1024 bytes", (&BodyTooLargeError{Limit: 1024}).Error())
+}
+
+func TestRoutePath(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase    string
+		path     string
+		expected string
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:    "without ids",
+			path:     "/youtrack/api/issues",
+			expected: "/youtrack/api/issues",
+		},
+		{
+			tcase:    "sprint",
+			path:     "/youtrack/api/agiles/108-1/sprints/109-5",
+			expected: "/youtrack/api/agiles/{id}/sprints/{id}",
+		},
+		{
+			tcase:    "digits in name",
+			path:     "/hub/api/rest/oauth2/token",
+			expected: "/hub/api/rest/oauth2/token",
+		},
+		{
+			tcase:    "numeric id",
+			path:     "/api/items/42/",
+			expected: "/api/items/{id}/",
+		},
+	}
+
+	for _, testUnit := range testTable {
+		assert.Equal(t, testUnit.expected, routePath(testUnit.path), testUnit.tcase)
+	}
+}
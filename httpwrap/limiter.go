@@ -0,0 +1,117 @@
+package httpwrap
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=limiter.go -destination=limiter_mocks.go -package=httpwrap doc github.com/golang/mock/gomock
+
+type limiterMetricser interface {
+	AddQueuedRequests(delta int)
+	ObserveRequestWait(seconds float64)
+}
+
+// Limiter limits requests rate with token bucket and concurrent requests count.
+// Limiter is safe for concurrent use and may be shared by several ClientWrap instances.
+type Limiter struct {
+	metricser limiterMetricser
+	rate      float64
+	burst     float64
+	slots     chan struct{}
+	now       func() time.Time
+	after     func(time.Duration) <-chan time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates Limiter instance.
+// Zero rate disables rate limit, zero maxConcurrent disables concurrent requests limit.
+// Burst is max requests count made without waiting, it is at least 1.
+func NewLimiter(rate float64, burst, maxConcurrent int, metricser limiterMetricser) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	var slots chan struct{}
+	if maxConcurrent > 0 {
+		slots = make(chan struct{}, maxConcurrent)
+	}
+
+	return &Limiter{
+		metricser: metricser,
+		rate:      rate,
+		burst:     float64(burst),
+		slots:     slots,
+		now:       time.Now,
+		after:     time.After,
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+}
+
+// Wait blocks until request is allowed or context is done, returned func must be called when request is done.
+// Context error is returned if request is not allowed before context is done.
+func (l *Limiter) Wait(ctx context.Context) (release func(), err error) {
+	l.metricser.AddQueuedRequests(1)
+	defer l.metricser.AddQueuedRequests(-1)
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	wait := l.reserve()
+	if wait > 0 {
+		select {
+		case <-l.after(wait):
+		case <-ctx.Done():
+			l.cancel()
+			l.release()
+			return nil, ctx.Err()
+		}
+	}
+	l.metricser.ObserveRequestWait(wait.Seconds())
+
+	return l.release, nil
+}
+
+// reserve takes token from bucket and returns time to wait until it is available.
+func (l *Limiter) reserve() time.Duration {
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel returns reserved token to bucket, so the following requests do not wait for canceled one.
+func (l *Limiter) cancel() {
+	l.mu.Lock()
+	l.tokens = math.Min(l.burst, l.tokens+1)
+	l.mu.Unlock()
+}
+
+func (l *Limiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
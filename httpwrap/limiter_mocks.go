@@ -0,0 +1,53 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: limiter.go
+
+// Package httpwrap is a generated GoMock package.
+package httpwrap
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MocklimiterMetricser is a mock of limiterMetricser interface
+type MocklimiterMetricser struct {
+	ctrl     *gomock.Controller
+	recorder *MocklimiterMetricserMockRecorder
+}
+
+// MocklimiterMetricserMockRecorder is the mock recorder for MocklimiterMetricser
+type MocklimiterMetricserMockRecorder struct {
+	mock *MocklimiterMetricser
+}
+
+// NewMocklimiterMetricser creates a new mock instance
+func NewMocklimiterMetricser(ctrl *gomock.Controller) *MocklimiterMetricser {
+	mock := &MocklimiterMetricser{ctrl: ctrl}
+	mock.recorder = &MocklimiterMetricserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MocklimiterMetricser) EXPECT() *MocklimiterMetricserMockRecorder {
+	return m.recorder
+}
+
+// AddQueuedRequests mocks base method
+func (m *MocklimiterMetricser) AddQueuedRequests(delta int) {
+	m.ctrl.Call(m, "AddQueuedRequests", delta)
+}
+
+// AddQueuedRequests indicates an expected call of AddQueuedRequests
+func (mr *MocklimiterMetricserMockRecorder) AddQueuedRequests(delta interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddQueuedRequests", reflect.TypeOf((*MocklimiterMetricser)(nil).AddQueuedRequests), delta)
+}
+
+// ObserveRequestWait mocks base method
+func (m *MocklimiterMetricser) ObserveRequestWait(seconds float64) {
+	m.ctrl.Call(m, "ObserveRequestWait", seconds)
+}
+
+// ObserveRequestWait indicates an expected call of ObserveRequestWait
+func (mr *MocklimiterMetricserMockRecorder) ObserveRequestWait(seconds interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveRequestWait", reflect.TypeOf((*MocklimiterMetricser)(nil).ObserveRequestWait), seconds)
+}
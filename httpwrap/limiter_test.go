@@ -0,0 +1,167 @@
+package httpwrap
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := NewLimiter(5, 0, 0, nil)
+	assert.Equal(t, float64(5), l.rate)
+	assert.Equal(t, float64(1), l.burst)
+	assert.Equal(t, float64(1), l.tokens)
+	assert.Nil(t, l.slots)
+
+	l = NewLimiter(0, 10, 4, nil)
+	assert.Equal(t, float64(10), l.burst)
+	assert.Equal(t, 4, cap(l.slots))
+}
+
+func TestLimiter_Wait_Rate(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricser := NewMocklimiterMetricser(ctrl)
+	metricser.EXPECT().AddQueuedRequests(gomock.Any()).AnyTimes()
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	var waited []time.Duration
+
+	l := NewLimiter(2, 2, 0, metricser)
+	l.now = func() time.Time { return now }
+	l.after = func(d time.Duration) <-chan time.Time {
+		waited = append(waited, d)
+		ch := make(chan time.Time, 1)
+		ch <- now.Add(d)
+		return ch
+	}
+	l.last = now
+
+	type testTableData struct {
+		tcase        string
+		advance      time.Duration
+		expectedWait time.Duration
+	}
+
+	testTable := []testTableData{
+		{tcase: "burst 1", advance: 0, expectedWait: 0},
+		{tcase: "burst 2", advance: 0, expectedWait: 0},
+		{tcase: "bucket is empty", advance: 0, expectedWait: 500 * time.Millisecond},
+		{tcase: "queued after reserved", advance: 0, expectedWait: time.Second},
+		{tcase: "bucket is refilled", advance: 10 * time.Second, expectedWait: 0},
+	}
+
+	for _, testUnit := range testTable {
+		now = now.Add(testUnit.advance)
+		waited = nil
+		metricser.EXPECT().ObserveRequestWait(testUnit.expectedWait.Seconds())
+
+		release, err := l.Wait(context.Background())
+		assert.NoError(t, err, testUnit.tcase)
+		release()
+
+		if testUnit.expectedWait > 0 {
+			assert.Equal(t, []time.Duration{testUnit.expectedWait}, waited, testUnit.tcase)
+		} else {
+			assert.Nil(t, waited, testUnit.tcase)
+		}
+	}
+}
+
+func TestLimiter_Wait_RateCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricser := NewMocklimiterMetricser(ctrl)
+	gomock.InOrder(
+		metricser.EXPECT().AddQueuedRequests(1),
+		metricser.EXPECT().AddQueuedRequests(-1),
+	)
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(1, 1, 1, metricser)
+	l.now = func() time.Time { return now }
+	l.last = now
+	l.tokens = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// context is canceled while waiting for token
+	l.after = func(time.Duration) <-chan time.Time {
+		cancel()
+		return nil
+	}
+
+	release, err := l.Wait(ctx)
+	assert.Nil(t, release)
+	assert.Equal(t, context.Canceled, err)
+	// reserved token is returned to bucket and concurrency slot is released
+	assert.Equal(t, float64(0), l.tokens)
+	assert.Len(t, l.slots, 0)
+}
+
+func TestLimiter_Wait_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricser := NewMocklimiterMetricser(ctrl)
+	metricser.EXPECT().ObserveRequestWait(float64(0)).Times(2)
+	metricser.EXPECT().AddQueuedRequests(1).Times(2)
+	metricser.EXPECT().AddQueuedRequests(-1).Times(2)
+
+	l := NewLimiter(0, 0, 1, metricser)
+	release, err := l.Wait(context.Background())
+	assert.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		release, err := l.Wait(context.Background())
+		assert.NoError(t, err)
+		release()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("concurrent requests limit is exceeded")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	<-done
+}
+
+func TestLimiter_Wait_ConcurrentCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricser := NewMocklimiterMetricser(ctrl)
+	gomock.InOrder(
+		metricser.EXPECT().AddQueuedRequests(1),
+		metricser.EXPECT().AddQueuedRequests(-1),
+	)
+
+	l := NewLimiter(0, 0, 1, metricser)
+	l.slots <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	release, err := l.Wait(ctx)
+	assert.Nil(t, release)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	// slot taken by other request is not released
+	assert.Len(t, l.slots, 1)
+}
@@ -0,0 +1,73 @@
+package httpwrap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// TransportOptions represents proxy and TLS settings of HTTP transport.
+type TransportOptions struct {
+	// Proxy is used for all requests, proxy from environment variables is used if nil
+	Proxy *url.URL
+	// CAFile is PEM bundle of certificates trusted in addition to system ones
+	CAFile string
+	// CertFile and KeyFile are PEM client certificate and key for mutual TLS
+	CertFile           string
+	KeyFile            string
+	MinVersion         uint16
+	InsecureSkipVerify bool
+}
+
+// NewTransport creates HTTP transport with passed options and defaults of http.DefaultTransport.
+// HTTP/2 is attempted as by default transport although TLS config is custom.
+func NewTransport(options TransportOptions) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if options.Proxy != nil {
+		transport.Proxy = http.ProxyURL(options.Proxy)
+	}
+	return transport, nil
+}
+
+func newTLSConfig(options TransportOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         options.MinVersion,
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+
+	if options.CAFile != "" {
+		pem, err := ioutil.ReadFile(options.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("CA file read error: %v", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found in CA file")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if options.CertFile != "" || options.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(options.CertFile, options.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate load error: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
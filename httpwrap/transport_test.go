@@ -0,0 +1,83 @@
+package httpwrap
+
+import (
+	"crypto/tls"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestNewTransport(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase       string
+		options     TransportOptions
+		checkFunc   func(transport *http.Transport)
+		expectedErr error
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:   "defaults",
+			options: TransportOptions{},
+			checkFunc: func(transport *http.Transport) {
+				assert.NotNil(t, transport.Proxy, "defaults")
+				assert.True(t, transport.ForceAttemptHTTP2, "defaults")
+				assert.Equal(t, &tls.Config{}, transport.TLSClientConfig, "defaults")
+			},
+		},
+		{
+			tcase:   "proxy",
+			options: TransportOptions{Proxy: &url.URL{Scheme: "http", Host: "proxy.test.com:3128"}},
+			checkFunc: func(transport *http.Transport) {
+				req, _ := http.NewRequest("GET", "https://www.test.com/", nil)
+				proxy, err := transport.Proxy(req)
+				assert.NoError(t, err, "proxy")
+				assert.Equal(t, &url.URL{Scheme: "http", Host: "proxy.test.com:3128"}, proxy, "proxy")
+			},
+		},
+		{
+			tcase: "tls",
+			options: TransportOptions{
+				CAFile:             "testdata/ca.pem",
+				CertFile:           "testdata/client.pem",
+				KeyFile:            "testdata/client-key.pem",
+				MinVersion:         tls.VersionTLS12,
+				InsecureSkipVerify: true,
+			},
+			checkFunc: func(transport *http.Transport) {
+				config := transport.TLSClientConfig
+				assert.NotNil(t, config.RootCAs, "tls")
+				assert.Len(t, config.Certificates, 1, "tls")
+				assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion, "tls")
+				assert.True(t, config.InsecureSkipVerify, "tls")
+			},
+		},
+		{
+			tcase:       "CA file not found",
+			options:     TransportOptions{CAFile: "testdata/not-found.pem"},
+			expectedErr: errors.New("CA file read error: open testdata/not-found.pem: no such file or directory"),
+		},
+		{
+			tcase:       "invalid CA file",
+			options:     TransportOptions{CAFile: "testdata/invalid.pem"},
+			expectedErr: errors.New("no certificates found in CA file"),
+		},
+		{
+			tcase:       "invalid client certificate",
+			options:     TransportOptions{CertFile: "testdata/invalid.pem", KeyFile: "testdata/client-key.pem"},
+			expectedErr: errors.New("client certificate load error: tls: failed to find any PEM data in certificate input"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		transport, err := NewTransport(testUnit.options)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.tcase)
+		if testUnit.checkFunc != nil && assert.NotNil(t, transport, testUnit.tcase) {
+			testUnit.checkFunc(transport)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"fmt"
+	kitlog "github.com/go-kit/kit/log"
+	kitlevel "github.com/go-kit/kit/log/level"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Level is log entry severity.
+type Level int
+
+// Log levels from the most verbose.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// Log formats.
+const (
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+const redacted = "[REDACTED]"
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel parses level name.
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level: %v", name)
+}
+
+// levelValues are go-kit level values and filter options of log levels.
+var levelValues = map[Level]struct {
+	value  kitlevel.Value
+	filter kitlevel.Option
+}{
+	LevelDebug: {kitlevel.DebugValue(), kitlevel.AllowDebug()},
+	LevelInfo:  {kitlevel.InfoValue(), kitlevel.AllowInfo()},
+	LevelWarn:  {kitlevel.WarnValue(), kitlevel.AllowWarn()},
+	LevelError: {kitlevel.ErrorValue(), kitlevel.AllowError()},
+}
+
+// Logger writes structured entries as logfmt or JSON lines with go-kit logger.
+// Values of authorization keys and headers are redacted.
+// Logger is safe for concurrent use, nil Logger discards all entries.
+type Logger struct {
+	logger kitlog.Logger
+	json   bool
+	now    func() time.Time
+}
+
+// New creates Logger writing entries of passed level and above to w.
+func New(w io.Writer, format string, level Level) (*Logger, error) {
+	var logger kitlog.Logger
+	switch format {
+	case FormatLogfmt:
+		logger = kitlog.NewLogfmtLogger(kitlog.NewSyncWriter(w))
+	case FormatJSON:
+		logger = kitlog.NewJSONLogger(kitlog.NewSyncWriter(w))
+	default:
+		return nil, fmt.Errorf("unknown log format: %v", format)
+	}
+
+	return &Logger{
+		logger: kitlevel.NewFilter(logger, levelValues[level].filter),
+		json:   format == FormatJSON,
+		now:    time.Now,
+	}, nil
+}
+
+// Debug writes debug entry with message and key-value pairs.
+func (l *Logger) Debug(msg string, keyvals ...interface{}) {
+	l.log(LevelDebug, msg, keyvals)
+}
+
+// Info writes info entry with message and key-value pairs.
+func (l *Logger) Info(msg string, keyvals ...interface{}) {
+	l.log(LevelInfo, msg, keyvals)
+}
+
+// Warn writes warning entry with message and key-value pairs.
+func (l *Logger) Warn(msg string, keyvals ...interface{}) {
+	l.log(LevelWarn, msg, keyvals)
+}
+
+// Error writes error entry with message and key-value pairs.
+func (l *Logger) Error(msg string, keyvals ...interface{}) {
+	l.log(LevelError, msg, keyvals)
+}
+
+func (l *Logger) log(level Level, msg string, keyvals []interface{}) {
+	if l == nil {
+		return
+	}
+
+	entry := make([]interface{}, 0, len(keyvals)+6)
+	entry = append(entry, "time", l.now().UTC().Format(time.RFC3339Nano), kitlevel.Key(), levelValues[level].value, "msg", msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		if i+1 == len(keyvals) {
+			entry = append(entry, key)
+			break
+		}
+
+		v := value(key, keyvals[i+1])
+		if !l.json {
+			// logfmt encoder does not support maps and slices
+			v = fmt.Sprint(v)
+		}
+		entry = append(entry, key, v)
+	}
+
+	_ = l.logger.Log(entry...)
+}
+
+// value converts value to its logged representation, authorization values are redacted.
+func value(key string, v interface{}) interface{} {
+	if isAuthorization(key) {
+		return redacted
+	}
+
+	switch v := v.(type) {
+	case map[string]string:
+		headers := make(map[string]string, len(v))
+		for name, val := range v {
+			if isAuthorization(name) {
+				val = redacted
+			}
+			headers[name] = val
+		}
+		return headers
+	case http.Header:
+		headers := make(http.Header, len(v))
+		for name, vals := range v {
+			if isAuthorization(name) {
+				vals = []string{redacted}
+			}
+			headers[name] = vals
+		}
+		return headers
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func isAuthorization(name string) bool {
+	return strings.EqualFold(name, "Authorization") || strings.EqualFold(name, "Proxy-Authorization")
+}
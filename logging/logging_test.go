@@ -0,0 +1,129 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		name          string
+		expectedLevel Level
+		expectedErr   error
+	}
+
+	testTable := []testTableData{
+		{
+			name:          "debug",
+			expectedLevel: LevelDebug,
+			expectedErr:   nil,
+		},
+		{
+			name:          "warn",
+			expectedLevel: LevelWarn,
+			expectedErr:   nil,
+		},
+		{
+			name:          "verbose",
+			expectedLevel: 0,
+			expectedErr:   errors.New("unknown log level: verbose"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		level, err := ParseLevel(testUnit.name)
+		assert.Equal(t, testUnit.expectedLevel, level, testUnit.name)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.name)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(&bytes.Buffer{}, "xml", LevelInfo)
+	assert.Equal(t, errors.New("unknown log format: xml"), err)
+}
+
+func TestLogger_Log(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase    string
+		format   string
+		level    Level
+		logFunc  func(l *Logger)
+		expected string
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:  "logfmt",
+			format: FormatLogfmt,
+			level:  LevelInfo,
+			logFunc: func(l *Logger) {
+				l.Info("refresh finished", "query", "test query", "duration", 1500*time.Millisecond, "size", 3, "empty", "")
+			},
+			expected: `time=2019-01-01T00:00:00Z level=info msg="refresh finished" query="test query" duration=1.5s size=3 empty=` + "\n",
+		},
+		{
+			tcase:  "json",
+			format: FormatJSON,
+			level:  LevelInfo,
+			logFunc: func(l *Logger) {
+				l.Error("refresh failed", "query", "test", "error", errors.New("request error"), "odd")
+			},
+			// go-kit JSON logger sorts keys
+			expected: `{"error":"request error","level":"error","msg":"refresh failed","odd":"(MISSING)","query":"test","time":"2019-01-01T00:00:00Z"}` + "\n",
+		},
+		{
+			tcase:  "level filtered",
+			format: FormatLogfmt,
+			level:  LevelWarn,
+			logFunc: func(l *Logger) {
+				l.Debug("debug")
+				l.Info("info")
+				l.Warn("warn")
+			},
+			expected: `time=2019-01-01T00:00:00Z level=warn msg=warn` + "\n",
+		},
+		{
+			tcase:  "redacted",
+			format: FormatJSON,
+			level:  LevelDebug,
+			logFunc: func(l *Logger) {
+				l.Debug("request",
+					"authorization", "Bearer abc",
+					"headers", map[string]string{"Authorization": "Bearer abc", "Accept": "application/json"},
+					"header", http.Header{"Proxy-Authorization": {"Basic abc"}},
+				)
+			},
+			expected: `{"authorization":"[REDACTED]","header":{"Proxy-Authorization":["[REDACTED]"]},` +
+				`"headers":{"Accept":"application/json","Authorization":"[REDACTED]"},"level":"debug","msg":"request","time":"2019-01-01T00:00:00Z"}` + "\n",
+		},
+	}
+
+	for _, testUnit := range testTable {
+		buf := &bytes.Buffer{}
+		l, err := New(buf, testUnit.format, testUnit.level)
+		assert.NoError(t, err, testUnit.tcase)
+		l.now = func() time.Time { return time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+		testUnit.logFunc(l)
+		assert.Equal(t, testUnit.expected, buf.String(), testUnit.tcase)
+	}
+}
+
+func TestLogger_Nil(t *testing.T) {
+	t.Parallel()
+
+	var l *Logger
+	assert.NotPanics(t, func() {
+		l.Error("refresh failed", "query", "test")
+	})
+}
@@ -6,10 +6,11 @@ import "fmt"
 type Issue struct {
 	ID    string
 	Title string
+	URL   string
 }
 
 // FullID returns uniq ID of issue.
-// Must depends on all issue fields.
+// Must depends on all issue fields (URL depends on ID).
 func (i Issue) FullID() string {
 	return fmt.Sprintf("%v %v", i.ID, i.Title)
 }
@@ -0,0 +1,9 @@
+package model
+
+// Project represents YouTrack project.
+type Project struct {
+	ShortName string
+	Name      string
+	Leader    string
+	Archived  bool
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Resolution represents resolved issue creation and resolution time.
+type Resolution struct {
+	IssueID  string
+	Project  string
+	Created  time.Time
+	Resolved time.Time
+}
+
+// Seconds returns time from issue creation to resolution in seconds.
+func (r Resolution) Seconds() float64 {
+	return r.Resolved.Sub(r.Created).Seconds()
+}
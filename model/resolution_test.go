@@ -0,0 +1,40 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestResolution_Seconds(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase      string
+		resolution Resolution
+		expected   float64
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "resolved in 2 hours",
+			resolution: Resolution{
+				Created:  time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+				Resolved: time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+			expected: 7200,
+		},
+		{
+			tcase: "resolved immediately",
+			resolution: Resolution{
+				Created:  time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+				Resolved: time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+			},
+			expected: 0,
+		},
+	}
+
+	for _, testUnit := range testTable {
+		assert.Equal(t, testUnit.expected, testUnit.resolution.Seconds(), testUnit.tcase)
+	}
+}
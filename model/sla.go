@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// SLAIssue represents issue with creation time and start time of SLA countdown.
+type SLAIssue struct {
+	Issue
+	Project string
+	Created time.Time
+	// Start is zero if SLA start field is not set
+	Start time.Time
+}
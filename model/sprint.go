@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Sprint represents agile board sprint.
+type Sprint struct {
+	Board  string
+	Name   string
+	Start  time.Time
+	Finish time.Time
+	// Columns holds issues count for each board column
+	Columns                    map[string]int
+	RemainingEstimationMinutes int
+}
+
+// AgileBoard represents agile board with columns mapped to values of column field.
+type AgileBoard struct {
+	ID   string
+	Name string
+	// CurrentSprintID is empty if board has no current sprint
+	CurrentSprintID string
+	ColumnField     string
+	// Columns holds column field values of each board column
+	Columns map[string][]string
+}
@@ -0,0 +1,11 @@
+package model
+
+// Query status severities.
+const (
+	SeverityOK       = "ok"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Severities lists all query status severities.
+var Severities = []string{SeverityOK, SeverityWarning, SeverityCritical}
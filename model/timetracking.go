@@ -0,0 +1,40 @@
+package model
+
+// WorkItem represents time tracking record of issue.
+type WorkItem struct {
+	ID      string
+	Author  string
+	Type    string
+	Minutes int
+}
+
+// TimeTracking represents time tracking data of issue.
+type TimeTracking struct {
+	IssueID           string
+	Project           string
+	EstimationMinutes int
+	WorkItems         []WorkItem
+}
+
+// SpentTime identifies time spent on issue by author with work type.
+type SpentTime struct {
+	IssueID string
+	Project string
+	Author  string
+	Type    string
+}
+
+// SpentTimes groups work items minutes by SpentTime.
+func (tt TimeTracking) SpentTimes() map[SpentTime]int {
+	spent := make(map[SpentTime]int)
+	for _, wi := range tt.WorkItems {
+		key := SpentTime{
+			IssueID: tt.IssueID,
+			Project: tt.Project,
+			Author:  wi.Author,
+			Type:    wi.Type,
+		}
+		spent[key] += wi.Minutes
+	}
+	return spent
+}
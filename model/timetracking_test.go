@@ -0,0 +1,49 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTimeTracking_SpentTimes(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase        string
+		timeTracking TimeTracking
+		expected     map[SpentTime]int
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "grouped by author and type",
+			timeTracking: TimeTracking{
+				IssueID: "YT-100",
+				Project: "YT",
+				WorkItems: []WorkItem{
+					{ID: "1", Author: "john", Type: "Development", Minutes: 60},
+					{ID: "2", Author: "john", Type: "Development", Minutes: 30},
+					{ID: "3", Author: "john", Type: "Testing", Minutes: 15},
+					{ID: "4", Author: "jane", Type: "Development", Minutes: 120},
+				},
+			},
+			expected: map[SpentTime]int{
+				{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}: 90,
+				{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Testing"}:     15,
+				{IssueID: "YT-100", Project: "YT", Author: "jane", Type: "Development"}: 120,
+			},
+		},
+		{
+			tcase: "no work items",
+			timeTracking: TimeTracking{
+				IssueID: "YT-100",
+				Project: "YT",
+			},
+			expected: map[SpentTime]int{},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		assert.Equal(t, testUnit.expected, testUnit.timeTracking.SpentTimes(), testUnit.tcase)
+	}
+}
@@ -1,72 +1,686 @@
 package monitoring
 
-import "github.com/krpn/youtrack-issues-prometheus-exporter/model"
+import (
+	"errors"
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"math"
+	"sync"
+	"time"
+)
 
 //go:generate mockgen -source=monitoring.go -destination=monitoring_mocks.go -package=monitoring doc github.com/golang/mock/gomock
 
-type getIssueser interface {
+// errNotModified is returned by refresh of query which source response has not changed, it is not a refresh error.
+var errNotModified = errors.New("response not modified")
+
+// Source gets issues for query. Sources are registered by name and selected per query, YouTrack is the default one.
+// Source may implement optional interfaces of other query types and settings.
+type Source interface {
 	GetIssues(query string) (issues map[string]model.Issue, err error)
 }
 
+type getIssuesIfChangeder interface {
+	GetIssuesIfChanged(query, hash string) (issues map[string]model.Issue, newHash string, changed bool, err error)
+}
+
+type getIssuesCounter interface {
+	GetIssuesCount(query string) (count int, err error)
+}
+
+type getSLAIssueser interface {
+	GetSLAIssues(query, startField string) (issues map[string]model.SLAIssue, err error)
+}
+
+type getTimeTrackinger interface {
+	GetTimeTracking(query, estimationField string) (trackings []model.TimeTracking, err error)
+}
+
+type getResolutionser interface {
+	GetResolutions(query string) (resolutions []model.Resolution, err error)
+}
+
+type getSprinter interface {
+	GetAgileBoards() (boards map[string]model.AgileBoard, err error)
+	GetSprint(board model.AgileBoard, estimationField string) (sprint model.Sprint, err error)
+}
+
+type getProjectser interface {
+	GetProjects() (projects []model.Project, err error)
+}
+
+// youTracker is YouTrack client which gets agile boards and projects, it is registered as YouTrack source too.
+type youTracker interface {
+	Source
+	getIssuesIfChangeder
+	getIssuesCounter
+	getSLAIssueser
+	getTimeTrackinger
+	getResolutionser
+	getSprinter
+	getProjectser
+}
+
 type metricser interface {
-	EnableMonitoring(queryName string, issue model.Issue)
-	DisableMonitoring(queryName string, issue model.Issue)
+	EnableMonitoring(queryName, project string, issue model.Issue)
+	DisableMonitoring(queryName, project string, issue model.Issue)
+	EnableInfo(queryName string, issue model.Issue)
+	DisableInfo(queryName string, issue model.Issue)
+	SetSLABreached(queryName, project, issueID string, breached bool)
+	SetSLA(queryName string, breachedCount int, timeToBreachSeconds float64)
+	SetSpentMinutes(queryName string, spent model.SpentTime, minutes int)
+	AddSpentMinutes(queryName string, spent model.SpentTime, minutes int)
+	SetEstimationMinutes(queryName, project, issueID string, minutes int)
+	SetSprint(sprint model.Sprint)
+	ResetSprint(sprint model.Sprint)
+	EnableProject(project model.Project)
+	DisableProject(project model.Project)
+	SetIssuesCount(queryName, project string, count int)
+	SetQueryStatus(queryName, severity string)
+	ObserveResolution(queryName, project string, seconds float64)
 	ErrorInc(queryName string, err error)
+	SprintErrorInc(board string, err error)
 }
 
 // Monitoring links YouTrack and Prometheus.
 type Monitoring struct {
-	issueser         getIssueser
+	youTracker       youTracker
 	metricser        metricser
 	lastActiveIssues map[string]map[string]model.Issue
-	queries          map[string]string
+	// lastHashes holds YouTrack response hashes of queries to skip unchanged responses
+	lastHashes map[string]string
+	// lastSLAIssues holds issues with started SLA countdown
+	lastSLAIssues    map[string]map[string]model.SLAIssue
+	lastTimeTracking map[string]timeTracking
+	// lastResolutions holds resolution time of observed issues to observe each resolution once
+	lastResolutions   map[string]map[string]time.Time
+	lastSprints       map[string]model.Sprint
+	lastProjects      map[string]model.Project
+	queries           map[string]config.Query
+	discoveredQueries map[string]config.Query
+	templates         map[string]config.QueryTemplate
+	sources           map[string]Source
+	boards            []config.AgileBoard
+	discovery         *config.ProjectDiscovery
+	now               func() time.Time
+	log               *logging.Logger
+	// status is read by HTTP handler concurrently with refresh
+	statusLock sync.RWMutex
+	status     map[string]QueryStatus
+}
+
+// workItemRetention is how long work items of issues which left query are remembered,
+// so work items of issue which is found again are not counted twice.
+const workItemRetention = 90 * 24 * time.Hour
+
+// timeTracking holds exported time tracking values of query.
+type timeTracking struct {
+	spent      map[model.SpentTime]int
+	estimation map[string]model.TimeTracking
+	// workItems holds counted work items by ID to count spent time once.
+	// It is nil before the first refresh.
+	workItems map[string]workItem
+}
+
+// workItem holds counted minutes of work item and time when it was found last.
+type workItem struct {
+	minutes int
+	seen    time.Time
+}
+
+func newTimeTracking() timeTracking {
+	return timeTracking{
+		spent:      make(map[model.SpentTime]int),
+		estimation: make(map[string]model.TimeTracking),
+	}
 }
 
 // New creates Monitoring instance.
-func New(issueser getIssueser, metricser metricser, queries map[string]string) *Monitoring {
+func New(youTracker youTracker, metricser metricser, c *config.Config, log *logging.Logger) *Monitoring {
 	lastActiveIssues := make(map[string]map[string]model.Issue)
-	for queryName := range queries {
-		lastActiveIssues[queryName] = make(map[string]model.Issue)
+	lastTimeTracking := make(map[string]timeTracking)
+	for queryName, query := range c.Queries {
+		switch query.Type {
+		case config.TypeWorkItems:
+			lastTimeTracking[queryName] = newTimeTracking()
+		default:
+			lastActiveIssues[queryName] = make(map[string]model.Issue)
+		}
+	}
+
+	// Templates with configured projects are already expanded to queries
+	templates := make(map[string]config.QueryTemplate)
+	for name, tmpl := range c.QueryTemplates {
+		if len(tmpl.Projects) == 0 {
+			templates[name] = tmpl
+		}
 	}
 
 	return &Monitoring{
-		issueser:         issueser,
-		metricser:        metricser,
-		lastActiveIssues: lastActiveIssues,
-		queries:          queries,
+		youTracker:        youTracker,
+		metricser:         metricser,
+		lastActiveIssues:  lastActiveIssues,
+		lastHashes:        make(map[string]string),
+		lastSLAIssues:     make(map[string]map[string]model.SLAIssue),
+		lastTimeTracking:  lastTimeTracking,
+		lastResolutions:   make(map[string]map[string]time.Time),
+		lastSprints:       make(map[string]model.Sprint, len(c.AgileBoards)),
+		lastProjects:      make(map[string]model.Project),
+		queries:           c.Queries,
+		discoveredQueries: make(map[string]config.Query),
+		templates:         templates,
+		sources:           make(map[string]Source),
+		boards:            c.AgileBoards,
+		discovery:         c.ProjectDiscovery,
+		now:               time.Now,
+		log:               log,
+		status:            make(map[string]QueryStatus),
 	}
 }
 
-// RefreshMetrics gets actual issues and refreshes metrics.
-func (m *Monitoring) RefreshMetrics() {
+// RegisterSource registers issue source which is selected by queries with passed source name.
+func (m *Monitoring) RegisterSource(name string, source Source) {
+	m.sources[name] = source
+}
+
+// RefreshMetrics gets actual issues and refreshes metrics. Returns count of failed refreshes.
+func (m *Monitoring) RefreshMetrics() (failed int) {
+	if m.discovery != nil {
+		err := m.refresh("query", config.DiscoveryQueryName, m.refreshProjects)
+		if err != nil {
+			m.metricser.ErrorInc(config.DiscoveryQueryName, err)
+			failed++
+		}
+	}
+
 	for queryName, query := range m.queries {
-		err := m.refreshMetrics(queryName, query)
+		if !m.refreshQuery(queryName, query) {
+			failed++
+		}
+	}
+
+	for queryName, query := range m.discoveredQueries {
+		if !m.refreshQuery(queryName, query) {
+			failed++
+		}
+	}
+
+	if len(m.boards) == 0 {
+		return failed
+	}
+
+	// Agile boards list is got once for all boards
+	agiles, agilesErr := m.youTracker.GetAgileBoards()
+	for _, board := range m.boards {
+		board := board
+		err := m.refresh("board", board.Name, func() (int, error) {
+			if agilesErr != nil {
+				return 0, agilesErr
+			}
+			return m.refreshSprint(board, agiles)
+		})
 		if err != nil {
-			m.metricser.ErrorInc(queryName, err)
+			m.metricser.SprintErrorInc(board.Name, err)
+			failed++
 		}
 	}
+
+	return failed
+}
+
+// RefreshQuery refreshes metrics of configured query as RefreshMetrics does, so query may be checked without refresh loop.
+// Returns found issues count and active issues of issues type query.
+func (m *Monitoring) RefreshQuery(queryName string) (int, map[string]model.Issue, error) {
+	query, ok := m.queries[queryName]
+	if !ok {
+		return 0, nil, fmt.Errorf("query is not configured: %v", queryName)
+	}
+
+	count, err := m.refreshMetrics(queryName, query)
+	if err != nil {
+		return 0, nil, err
+	}
+	return count, m.lastActiveIssues[queryName], nil
 }
 
-func (m *Monitoring) refreshMetrics(queryName, query string) error {
-	issues, err := m.issueser.GetIssues(query)
+// refreshQuery refreshes query metrics and counts refresh error. Returns false if refresh failed.
+func (m *Monitoring) refreshQuery(queryName string, query config.Query) bool {
+	err := m.refresh("query", queryName, func() (int, error) {
+		return m.refreshMetrics(queryName, query)
+	})
 	if err != nil {
+		m.metricser.ErrorInc(queryName, err)
+		return false
+	}
+	return true
+}
+
+// refresh logs refresh duration and result size, kind is log key of refreshed name: query or board.
+// Returns refresh error, not modified response is not an error.
+func (m *Monitoring) refresh(kind, name string, refresh func() (int, error)) error {
+	start := time.Now()
+	size, err := refresh()
+	duration := time.Since(start)
+
+	switch err {
+	case nil:
+		m.log.Info("refresh finished", kind, name, "duration", duration, "size", size)
+	case errNotModified:
+		m.log.Info("refresh skipped, response not modified", kind, name, "duration", duration)
+	default:
+		m.log.Error("refresh failed", kind, name, "duration", duration, "error", err)
 		return err
 	}
+	return nil
+}
+
+// refreshMetrics refreshes query metrics and returns found issues count.
+// Returns errNotModified if source response has not changed, so metrics are not refreshed.
+func (m *Monitoring) refreshMetrics(queryName string, query config.Query) (int, error) {
+	switch query.Type {
+	case config.TypeWorkItems:
+		return m.refreshTimeTracking(queryName, query)
+	case config.TypeCount:
+		return m.refreshCount(queryName, query)
+	case config.TypeResolution:
+		return m.refreshResolution(queryName, query)
+	default:
+		return m.refreshIssues(queryName, query)
+	}
+}
+
+// resetMetrics resets metrics of query which is not refreshed anymore.
+func (m *Monitoring) resetMetrics(queryName string, query config.Query) {
+	switch query.Type {
+	case config.TypeWorkItems:
+		last := m.lastTimeTracking[queryName]
+		for key := range last.spent {
+			m.metricser.SetSpentMinutes(queryName, key, 0)
+		}
+		for issueID, tt := range last.estimation {
+			m.metricser.SetEstimationMinutes(queryName, tt.Project, issueID, 0)
+		}
+		delete(m.lastTimeTracking, queryName)
+	case config.TypeCount:
+		m.metricser.SetIssuesCount(queryName, query.Project, 0)
+		if query.Thresholds != nil {
+			m.resetStatus(queryName)
+		}
+	case config.TypeResolution:
+		// Histogram observations can not be reset
+		delete(m.lastResolutions, queryName)
+	default:
+		for _, issue := range m.lastActiveIssues[queryName] {
+			m.disableMonitoring(queryName, query, issue)
+		}
+		delete(m.lastActiveIssues, queryName)
+		delete(m.lastHashes, queryName)
+
+		if query.SLA != nil {
+			for _, issue := range m.lastSLAIssues[queryName] {
+				m.metricser.SetSLABreached(queryName, issue.Project, issue.ID, false)
+			}
+			m.metricser.SetSLA(queryName, 0, math.Inf(1))
+			delete(m.lastSLAIssues, queryName)
+		}
+		if query.Thresholds != nil {
+			m.resetStatus(queryName)
+		}
+	}
+}
+
+func (m *Monitoring) refreshIssues(queryName string, query config.Query) (int, error) {
+	var (
+		issues    map[string]model.Issue
+		slaIssues map[string]model.SLAIssue
+		err       error
+	)
+
+	if query.SLA != nil || query.Thresholds.HasAge() {
+		slaIssues, err = m.getSLAIssues(query)
+		issues = make(map[string]model.Issue, len(slaIssues))
+		for key, issue := range slaIssues {
+			issues[key] = issue.Issue
+		}
+	} else {
+		issues, err = m.getIssues(queryName, query)
+	}
+	if err != nil {
+		return 0, err
+	}
 
 	// Disable irrelevant issues
 	for key, issue := range m.lastActiveIssues[queryName] {
 		if _, ok := issues[key]; !ok {
-			m.metricser.DisableMonitoring(queryName, issue)
+			m.disableMonitoring(queryName, query, issue)
 		}
 	}
 
 	// Enable relevant issues
 	for key, issue := range issues {
 		if _, ok := m.lastActiveIssues[queryName][key]; !ok {
-			m.metricser.EnableMonitoring(queryName, issue)
+			m.enableMonitoring(queryName, query, issue)
 		}
 	}
 
 	m.lastActiveIssues[queryName] = issues
-	return nil
+
+	if query.SLA != nil {
+		m.refreshSLA(queryName, query, slaIssues)
+	}
+	if query.Thresholds != nil {
+		m.refreshStatus(queryName, query, slaIssues, len(issues))
+	}
+	return len(issues), nil
+}
+
+// getSLAIssues gets query issues with SLA start time, issues without SLA have creation time only.
+func (m *Monitoring) getSLAIssues(query config.Query) (map[string]model.SLAIssue, error) {
+	source, err := m.source(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var startField string
+	if query.SLA != nil {
+		startField = query.SLA.StartField
+	}
+	return source.(getSLAIssueser).GetSLAIssues(query.Query, startField)
+}
+
+// refreshSLA exports SLA breach of issues, breached issues count and time to the nearest breach.
+// Time to breach is +Inf if there are no issues which may breach SLA.
+func (m *Monitoring) refreshSLA(queryName string, query config.Query, issues map[string]model.SLAIssue) {
+	threshold := time.Duration(query.SLA.ThresholdSeconds) * time.Second
+	now := m.now()
+
+	// Issues without SLA start are skipped
+	started := make(map[string]model.SLAIssue, len(issues))
+	for key, issue := range issues {
+		if !issue.Start.IsZero() {
+			started[key] = issue
+		}
+	}
+
+	// Reset irrelevant issues
+	for key, issue := range m.lastSLAIssues[queryName] {
+		if _, ok := started[key]; !ok {
+			m.metricser.SetSLABreached(queryName, issue.Project, issue.ID, false)
+		}
+	}
+
+	breachedCount := 0
+	timeToBreach := math.Inf(1)
+	for _, issue := range started {
+		left := issue.Start.Add(threshold).Sub(now)
+		breached := left <= 0
+		if breached {
+			breachedCount++
+		} else {
+			timeToBreach = math.Min(timeToBreach, left.Seconds())
+		}
+		m.metricser.SetSLABreached(queryName, issue.Project, issue.ID, breached)
+	}
+
+	m.metricser.SetSLA(queryName, breachedCount, timeToBreach)
+	m.lastSLAIssues[queryName] = started
+}
+
+func (m *Monitoring) refreshCount(queryName string, query config.Query) (int, error) {
+	count, err := m.getIssuesCount(queryName, query)
+	if err != nil {
+		return 0, err
+	}
+
+	m.metricser.SetIssuesCount(queryName, query.Project, count)
+	if query.Thresholds != nil {
+		m.refreshStatus(queryName, query, nil, count)
+	}
+	return count, nil
+}
+
+// getIssuesCount gets query issues count, sources like YouTrack count issues themselves, so issues are not downloaded.
+func (m *Monitoring) getIssuesCount(queryName string, query config.Query) (int, error) {
+	source, err := m.source(query)
+	if err != nil {
+		return 0, err
+	}
+	if counter, ok := source.(getIssuesCounter); ok {
+		return counter.GetIssuesCount(query.Query)
+	}
+
+	issues, err := m.getIssues(queryName, query)
+	if err != nil {
+		return 0, err
+	}
+	return len(issues), nil
+}
+
+// getIssues gets query issues from query source.
+// Returns errNotModified if source response has not changed since the previous refresh of query.
+func (m *Monitoring) getIssues(queryName string, query config.Query) (map[string]model.Issue, error) {
+	source, err := m.source(query)
+	if err != nil {
+		return nil, err
+	}
+
+	changeder, ok := source.(getIssuesIfChangeder)
+	if !ok {
+		return source.GetIssues(query.Query)
+	}
+
+	issues, hash, changed, err := changeder.GetIssuesIfChanged(query.Query, m.lastHashes[queryName])
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, errNotModified
+	}
+	m.lastHashes[queryName] = hash
+	return issues, nil
+}
+
+func (m *Monitoring) refreshTimeTracking(queryName string, query config.Query) (int, error) {
+	source, err := m.source(query)
+	if err != nil {
+		return 0, err
+	}
+	trackings, err := source.(getTimeTrackinger).GetTimeTracking(query.Query, query.EstimationField)
+	if err != nil {
+		return 0, err
+	}
+
+	last, ok := m.lastTimeTracking[queryName]
+	if !ok {
+		last = newTimeTracking()
+	}
+
+	// Work items existing before the first refresh are not counted, so restart does not count them again
+	seeded := last.workItems != nil
+
+	current := timeTracking{
+		spent:      make(map[model.SpentTime]int),
+		estimation: make(map[string]model.TimeTracking, len(trackings)),
+		workItems:  make(map[string]workItem, len(last.workItems)),
+	}
+
+	// Work items of issues which left query are kept until retention is passed
+	now := m.now()
+	for id, wi := range last.workItems {
+		if now.Sub(wi.seen) < workItemRetention {
+			current.workItems[id] = wi
+		}
+	}
+
+	for _, tt := range trackings {
+		current.estimation[tt.IssueID] = tt
+		for key, minutes := range tt.SpentTimes() {
+			current.spent[key] += minutes
+		}
+
+		// Count only new or increased work items
+		for _, wi := range tt.WorkItems {
+			if delta := wi.Minutes - current.workItems[wi.ID].minutes; seeded && delta > 0 {
+				m.metricser.AddSpentMinutes(queryName, model.SpentTime{
+					IssueID: tt.IssueID,
+					Project: tt.Project,
+					Author:  wi.Author,
+					Type:    wi.Type,
+				}, delta)
+			}
+			current.workItems[wi.ID] = workItem{minutes: wi.Minutes, seen: now}
+		}
+	}
+
+	// Reset irrelevant values
+	for key := range last.spent {
+		if _, ok := current.spent[key]; !ok {
+			m.metricser.SetSpentMinutes(queryName, key, 0)
+		}
+	}
+	for issueID, tt := range last.estimation {
+		if _, ok := current.estimation[issueID]; !ok {
+			m.metricser.SetEstimationMinutes(queryName, tt.Project, issueID, 0)
+		}
+	}
+
+	// Set relevant values
+	for key, minutes := range current.spent {
+		m.metricser.SetSpentMinutes(queryName, key, minutes)
+	}
+	for issueID, tt := range current.estimation {
+		m.metricser.SetEstimationMinutes(queryName, tt.Project, issueID, tt.EstimationMinutes)
+	}
+
+	m.lastTimeTracking[queryName] = current
+	return len(trackings), nil
+}
+
+func (m *Monitoring) refreshResolution(queryName string, query config.Query) (int, error) {
+	since := m.now().Add(-time.Duration(query.ResolutionWindowSeconds) * time.Second)
+
+	// YouTrack filters by date only, so a day margin is added for time zones difference and precise window is checked below
+	from := since.AddDate(0, 0, -1).Format("2006-01-02")
+	source, err := m.source(query)
+	if err != nil {
+		return 0, err
+	}
+	resolutions, err := source.(getResolutionser).GetResolutions(fmt.Sprintf("%v resolved date: %v .. Today", query.Query, from))
+	if err != nil {
+		return 0, err
+	}
+
+	// Resolutions found by the first refresh may be observed before restart, so they are only remembered
+	observed, seeded := m.lastResolutions[queryName]
+	if !seeded {
+		observed = make(map[string]time.Time)
+		m.lastResolutions[queryName] = observed
+	}
+
+	// Observe new resolutions, reopened and resolved again issue is observed again
+	for _, r := range resolutions {
+		if r.Resolved.IsZero() || r.Resolved.Before(since) {
+			continue
+		}
+		if resolved, ok := observed[r.IssueID]; ok && resolved.Equal(r.Resolved) {
+			continue
+		}
+		if seeded {
+			m.metricser.ObserveResolution(queryName, r.Project, r.Seconds())
+		}
+		observed[r.IssueID] = r.Resolved
+	}
+
+	// Forget resolutions out of window, they are not found anymore
+	for issueID, resolved := range observed {
+		if resolved.Before(since) {
+			delete(observed, issueID)
+		}
+	}
+
+	return len(resolutions), nil
+}
+
+func (m *Monitoring) refreshSprint(board config.AgileBoard, agiles map[string]model.AgileBoard) (int, error) {
+	agile, ok := agiles[board.Name]
+	if !ok {
+		return 0, fmt.Errorf("agile board not found: %v", board.Name)
+	}
+
+	sprint, err := m.youTracker.GetSprint(agile, board.EstimationField)
+	if err != nil {
+		return 0, err
+	}
+
+	// Reset irrelevant values: all values of finished sprint or removed columns of current sprint
+	if last, ok := m.lastSprints[board.Name]; ok {
+		if last.Name == sprint.Name {
+			last.Columns = removedColumns(last.Columns, sprint.Columns)
+		}
+		m.metricser.ResetSprint(last)
+	}
+
+	m.metricser.SetSprint(sprint)
+	m.lastSprints[board.Name] = sprint
+
+	issuesCount := 0
+	for _, count := range sprint.Columns {
+		issuesCount += count
+	}
+	return issuesCount, nil
+}
+
+func (m *Monitoring) enableMonitoring(queryName string, query config.Query, issue model.Issue) {
+	issue = truncateTitle(issue, query.TitleMaxLength)
+	if query.Title == config.TitleInfo {
+		m.metricser.EnableInfo(queryName, issue)
+	}
+	m.metricser.EnableMonitoring(queryName, query.Project, labelIssue(issue, query))
+}
+
+func (m *Monitoring) disableMonitoring(queryName string, query config.Query, issue model.Issue) {
+	issue = truncateTitle(issue, query.TitleMaxLength)
+	if query.Title == config.TitleInfo {
+		m.metricser.DisableInfo(queryName, issue)
+	}
+	m.metricser.DisableMonitoring(queryName, query.Project, labelIssue(issue, query))
+}
+
+// truncateTitle cuts issue title to maxLength runes, zero maxLength means no limit.
+func truncateTitle(issue model.Issue, maxLength int) model.Issue {
+	if maxLength <= 0 {
+		return issue
+	}
+
+	title := []rune(issue.Title)
+	if len(title) > maxLength {
+		issue.Title = string(title[:maxLength])
+	}
+
+	return issue
+}
+
+// labelIssue clears fields which must not be exported in issue metric labels.
+// Empty label value is equal to absent label for Prometheus.
+func labelIssue(issue model.Issue, query config.Query) model.Issue {
+	if query.Title != config.TitleLabel {
+		issue.Title = ""
+	}
+	if !query.URLLabel {
+		issue.URL = ""
+	}
+	return issue
+}
+
+// removedColumns returns last columns which are absent in current columns.
+func removedColumns(last, current map[string]int) map[string]int {
+	removed := make(map[string]int)
+	for column, count := range last {
+		if _, ok := current[column]; !ok {
+			removed[column] = count
+		}
+	}
+	return removed
 }
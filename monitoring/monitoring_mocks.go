@@ -10,31 +10,31 @@ import (
 	reflect "reflect"
 )
 
-// MockgetIssueser is a mock of getIssueser interface
-type MockgetIssueser struct {
+// MockSource is a mock of Source interface
+type MockSource struct {
 	ctrl     *gomock.Controller
-	recorder *MockgetIssueserMockRecorder
+	recorder *MockSourceMockRecorder
 }
 
-// MockgetIssueserMockRecorder is the mock recorder for MockgetIssueser
-type MockgetIssueserMockRecorder struct {
-	mock *MockgetIssueser
+// MockSourceMockRecorder is the mock recorder for MockSource
+type MockSourceMockRecorder struct {
+	mock *MockSource
 }
 
-// NewMockgetIssueser creates a new mock instance
-func NewMockgetIssueser(ctrl *gomock.Controller) *MockgetIssueser {
-	mock := &MockgetIssueser{ctrl: ctrl}
-	mock.recorder = &MockgetIssueserMockRecorder{mock}
+// NewMockSource creates a new mock instance
+func NewMockSource(ctrl *gomock.Controller) *MockSource {
+	mock := &MockSource{ctrl: ctrl}
+	mock.recorder = &MockSourceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use
-func (m *MockgetIssueser) EXPECT() *MockgetIssueserMockRecorder {
+func (m *MockSource) EXPECT() *MockSourceMockRecorder {
 	return m.recorder
 }
 
 // GetIssues mocks base method
-func (m *MockgetIssueser) GetIssues(query string) (map[string]model.Issue, error) {
+func (m *MockSource) GetIssues(query string) (map[string]model.Issue, error) {
 	ret := m.ctrl.Call(m, "GetIssues", query)
 	ret0, _ := ret[0].(map[string]model.Issue)
 	ret1, _ := ret[1].(error)
@@ -42,8 +42,417 @@ func (m *MockgetIssueser) GetIssues(query string) (map[string]model.Issue, error
 }
 
 // GetIssues indicates an expected call of GetIssues
-func (mr *MockgetIssueserMockRecorder) GetIssues(query interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssues", reflect.TypeOf((*MockgetIssueser)(nil).GetIssues), query)
+func (mr *MockSourceMockRecorder) GetIssues(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssues", reflect.TypeOf((*MockSource)(nil).GetIssues), query)
+}
+
+// MockgetIssuesIfChangeder is a mock of getIssuesIfChangeder interface
+type MockgetIssuesIfChangeder struct {
+	ctrl     *gomock.Controller
+	recorder *MockgetIssuesIfChangederMockRecorder
+}
+
+// MockgetIssuesIfChangederMockRecorder is the mock recorder for MockgetIssuesIfChangeder
+type MockgetIssuesIfChangederMockRecorder struct {
+	mock *MockgetIssuesIfChangeder
+}
+
+// NewMockgetIssuesIfChangeder creates a new mock instance
+func NewMockgetIssuesIfChangeder(ctrl *gomock.Controller) *MockgetIssuesIfChangeder {
+	mock := &MockgetIssuesIfChangeder{ctrl: ctrl}
+	mock.recorder = &MockgetIssuesIfChangederMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockgetIssuesIfChangeder) EXPECT() *MockgetIssuesIfChangederMockRecorder {
+	return m.recorder
+}
+
+// GetIssuesIfChanged mocks base method
+func (m *MockgetIssuesIfChangeder) GetIssuesIfChanged(query, hash string) (map[string]model.Issue, string, bool, error) {
+	ret := m.ctrl.Call(m, "GetIssuesIfChanged", query, hash)
+	ret0, _ := ret[0].(map[string]model.Issue)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetIssuesIfChanged indicates an expected call of GetIssuesIfChanged
+func (mr *MockgetIssuesIfChangederMockRecorder) GetIssuesIfChanged(query, hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuesIfChanged", reflect.TypeOf((*MockgetIssuesIfChangeder)(nil).GetIssuesIfChanged), query, hash)
+}
+
+// MockgetIssuesCounter is a mock of getIssuesCounter interface
+type MockgetIssuesCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockgetIssuesCounterMockRecorder
+}
+
+// MockgetIssuesCounterMockRecorder is the mock recorder for MockgetIssuesCounter
+type MockgetIssuesCounterMockRecorder struct {
+	mock *MockgetIssuesCounter
+}
+
+// NewMockgetIssuesCounter creates a new mock instance
+func NewMockgetIssuesCounter(ctrl *gomock.Controller) *MockgetIssuesCounter {
+	mock := &MockgetIssuesCounter{ctrl: ctrl}
+	mock.recorder = &MockgetIssuesCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockgetIssuesCounter) EXPECT() *MockgetIssuesCounterMockRecorder {
+	return m.recorder
+}
+
+// GetIssuesCount mocks base method
+func (m *MockgetIssuesCounter) GetIssuesCount(query string) (int, error) {
+	ret := m.ctrl.Call(m, "GetIssuesCount", query)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuesCount indicates an expected call of GetIssuesCount
+func (mr *MockgetIssuesCounterMockRecorder) GetIssuesCount(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuesCount", reflect.TypeOf((*MockgetIssuesCounter)(nil).GetIssuesCount), query)
+}
+
+// MockgetSLAIssueser is a mock of getSLAIssueser interface
+type MockgetSLAIssueser struct {
+	ctrl     *gomock.Controller
+	recorder *MockgetSLAIssueserMockRecorder
+}
+
+// MockgetSLAIssueserMockRecorder is the mock recorder for MockgetSLAIssueser
+type MockgetSLAIssueserMockRecorder struct {
+	mock *MockgetSLAIssueser
+}
+
+// NewMockgetSLAIssueser creates a new mock instance
+func NewMockgetSLAIssueser(ctrl *gomock.Controller) *MockgetSLAIssueser {
+	mock := &MockgetSLAIssueser{ctrl: ctrl}
+	mock.recorder = &MockgetSLAIssueserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockgetSLAIssueser) EXPECT() *MockgetSLAIssueserMockRecorder {
+	return m.recorder
+}
+
+// GetSLAIssues mocks base method
+func (m *MockgetSLAIssueser) GetSLAIssues(query, startField string) (map[string]model.SLAIssue, error) {
+	ret := m.ctrl.Call(m, "GetSLAIssues", query, startField)
+	ret0, _ := ret[0].(map[string]model.SLAIssue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSLAIssues indicates an expected call of GetSLAIssues
+func (mr *MockgetSLAIssueserMockRecorder) GetSLAIssues(query, startField interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSLAIssues", reflect.TypeOf((*MockgetSLAIssueser)(nil).GetSLAIssues), query, startField)
+}
+
+// MockgetTimeTrackinger is a mock of getTimeTrackinger interface
+type MockgetTimeTrackinger struct {
+	ctrl     *gomock.Controller
+	recorder *MockgetTimeTrackingerMockRecorder
+}
+
+// MockgetTimeTrackingerMockRecorder is the mock recorder for MockgetTimeTrackinger
+type MockgetTimeTrackingerMockRecorder struct {
+	mock *MockgetTimeTrackinger
+}
+
+// NewMockgetTimeTrackinger creates a new mock instance
+func NewMockgetTimeTrackinger(ctrl *gomock.Controller) *MockgetTimeTrackinger {
+	mock := &MockgetTimeTrackinger{ctrl: ctrl}
+	mock.recorder = &MockgetTimeTrackingerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockgetTimeTrackinger) EXPECT() *MockgetTimeTrackingerMockRecorder {
+	return m.recorder
+}
+
+// GetTimeTracking mocks base method
+func (m *MockgetTimeTrackinger) GetTimeTracking(query, estimationField string) ([]model.TimeTracking, error) {
+	ret := m.ctrl.Call(m, "GetTimeTracking", query, estimationField)
+	ret0, _ := ret[0].([]model.TimeTracking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeTracking indicates an expected call of GetTimeTracking
+func (mr *MockgetTimeTrackingerMockRecorder) GetTimeTracking(query, estimationField interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeTracking", reflect.TypeOf((*MockgetTimeTrackinger)(nil).GetTimeTracking), query, estimationField)
+}
+
+// MockgetResolutionser is a mock of getResolutionser interface
+type MockgetResolutionser struct {
+	ctrl     *gomock.Controller
+	recorder *MockgetResolutionserMockRecorder
+}
+
+// MockgetResolutionserMockRecorder is the mock recorder for MockgetResolutionser
+type MockgetResolutionserMockRecorder struct {
+	mock *MockgetResolutionser
+}
+
+// NewMockgetResolutionser creates a new mock instance
+func NewMockgetResolutionser(ctrl *gomock.Controller) *MockgetResolutionser {
+	mock := &MockgetResolutionser{ctrl: ctrl}
+	mock.recorder = &MockgetResolutionserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockgetResolutionser) EXPECT() *MockgetResolutionserMockRecorder {
+	return m.recorder
+}
+
+// GetResolutions mocks base method
+func (m *MockgetResolutionser) GetResolutions(query string) ([]model.Resolution, error) {
+	ret := m.ctrl.Call(m, "GetResolutions", query)
+	ret0, _ := ret[0].([]model.Resolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResolutions indicates an expected call of GetResolutions
+func (mr *MockgetResolutionserMockRecorder) GetResolutions(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResolutions", reflect.TypeOf((*MockgetResolutionser)(nil).GetResolutions), query)
+}
+
+// MockgetSprinter is a mock of getSprinter interface
+type MockgetSprinter struct {
+	ctrl     *gomock.Controller
+	recorder *MockgetSprinterMockRecorder
+}
+
+// MockgetSprinterMockRecorder is the mock recorder for MockgetSprinter
+type MockgetSprinterMockRecorder struct {
+	mock *MockgetSprinter
+}
+
+// NewMockgetSprinter creates a new mock instance
+func NewMockgetSprinter(ctrl *gomock.Controller) *MockgetSprinter {
+	mock := &MockgetSprinter{ctrl: ctrl}
+	mock.recorder = &MockgetSprinterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockgetSprinter) EXPECT() *MockgetSprinterMockRecorder {
+	return m.recorder
+}
+
+// GetAgileBoards mocks base method
+func (m *MockgetSprinter) GetAgileBoards() (map[string]model.AgileBoard, error) {
+	ret := m.ctrl.Call(m, "GetAgileBoards")
+	ret0, _ := ret[0].(map[string]model.AgileBoard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgileBoards indicates an expected call of GetAgileBoards
+func (mr *MockgetSprinterMockRecorder) GetAgileBoards() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgileBoards", reflect.TypeOf((*MockgetSprinter)(nil).GetAgileBoards))
+}
+
+// GetSprint mocks base method
+func (m *MockgetSprinter) GetSprint(board model.AgileBoard, estimationField string) (model.Sprint, error) {
+	ret := m.ctrl.Call(m, "GetSprint", board, estimationField)
+	ret0, _ := ret[0].(model.Sprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSprint indicates an expected call of GetSprint
+func (mr *MockgetSprinterMockRecorder) GetSprint(board, estimationField interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSprint", reflect.TypeOf((*MockgetSprinter)(nil).GetSprint), board, estimationField)
+}
+
+// MockgetProjectser is a mock of getProjectser interface
+type MockgetProjectser struct {
+	ctrl     *gomock.Controller
+	recorder *MockgetProjectserMockRecorder
+}
+
+// MockgetProjectserMockRecorder is the mock recorder for MockgetProjectser
+type MockgetProjectserMockRecorder struct {
+	mock *MockgetProjectser
+}
+
+// NewMockgetProjectser creates a new mock instance
+func NewMockgetProjectser(ctrl *gomock.Controller) *MockgetProjectser {
+	mock := &MockgetProjectser{ctrl: ctrl}
+	mock.recorder = &MockgetProjectserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockgetProjectser) EXPECT() *MockgetProjectserMockRecorder {
+	return m.recorder
+}
+
+// GetProjects mocks base method
+func (m *MockgetProjectser) GetProjects() ([]model.Project, error) {
+	ret := m.ctrl.Call(m, "GetProjects")
+	ret0, _ := ret[0].([]model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjects indicates an expected call of GetProjects
+func (mr *MockgetProjectserMockRecorder) GetProjects() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjects", reflect.TypeOf((*MockgetProjectser)(nil).GetProjects))
+}
+
+// MockyouTracker is a mock of youTracker interface
+type MockyouTracker struct {
+	ctrl     *gomock.Controller
+	recorder *MockyouTrackerMockRecorder
+}
+
+// MockyouTrackerMockRecorder is the mock recorder for MockyouTracker
+type MockyouTrackerMockRecorder struct {
+	mock *MockyouTracker
+}
+
+// NewMockyouTracker creates a new mock instance
+func NewMockyouTracker(ctrl *gomock.Controller) *MockyouTracker {
+	mock := &MockyouTracker{ctrl: ctrl}
+	mock.recorder = &MockyouTrackerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockyouTracker) EXPECT() *MockyouTrackerMockRecorder {
+	return m.recorder
+}
+
+// GetIssues mocks base method
+func (m *MockyouTracker) GetIssues(query string) (map[string]model.Issue, error) {
+	ret := m.ctrl.Call(m, "GetIssues", query)
+	ret0, _ := ret[0].(map[string]model.Issue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssues indicates an expected call of GetIssues
+func (mr *MockyouTrackerMockRecorder) GetIssues(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssues", reflect.TypeOf((*MockyouTracker)(nil).GetIssues), query)
+}
+
+// GetIssuesIfChanged mocks base method
+func (m *MockyouTracker) GetIssuesIfChanged(query, hash string) (map[string]model.Issue, string, bool, error) {
+	ret := m.ctrl.Call(m, "GetIssuesIfChanged", query, hash)
+	ret0, _ := ret[0].(map[string]model.Issue)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(bool)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetIssuesIfChanged indicates an expected call of GetIssuesIfChanged
+func (mr *MockyouTrackerMockRecorder) GetIssuesIfChanged(query, hash interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuesIfChanged", reflect.TypeOf((*MockyouTracker)(nil).GetIssuesIfChanged), query, hash)
+}
+
+// GetIssuesCount mocks base method
+func (m *MockyouTracker) GetIssuesCount(query string) (int, error) {
+	ret := m.ctrl.Call(m, "GetIssuesCount", query)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIssuesCount indicates an expected call of GetIssuesCount
+func (mr *MockyouTrackerMockRecorder) GetIssuesCount(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIssuesCount", reflect.TypeOf((*MockyouTracker)(nil).GetIssuesCount), query)
+}
+
+// GetSLAIssues mocks base method
+func (m *MockyouTracker) GetSLAIssues(query, startField string) (map[string]model.SLAIssue, error) {
+	ret := m.ctrl.Call(m, "GetSLAIssues", query, startField)
+	ret0, _ := ret[0].(map[string]model.SLAIssue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSLAIssues indicates an expected call of GetSLAIssues
+func (mr *MockyouTrackerMockRecorder) GetSLAIssues(query, startField interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSLAIssues", reflect.TypeOf((*MockyouTracker)(nil).GetSLAIssues), query, startField)
+}
+
+// GetTimeTracking mocks base method
+func (m *MockyouTracker) GetTimeTracking(query, estimationField string) ([]model.TimeTracking, error) {
+	ret := m.ctrl.Call(m, "GetTimeTracking", query, estimationField)
+	ret0, _ := ret[0].([]model.TimeTracking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTimeTracking indicates an expected call of GetTimeTracking
+func (mr *MockyouTrackerMockRecorder) GetTimeTracking(query, estimationField interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTimeTracking", reflect.TypeOf((*MockyouTracker)(nil).GetTimeTracking), query, estimationField)
+}
+
+// GetResolutions mocks base method
+func (m *MockyouTracker) GetResolutions(query string) ([]model.Resolution, error) {
+	ret := m.ctrl.Call(m, "GetResolutions", query)
+	ret0, _ := ret[0].([]model.Resolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResolutions indicates an expected call of GetResolutions
+func (mr *MockyouTrackerMockRecorder) GetResolutions(query interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResolutions", reflect.TypeOf((*MockyouTracker)(nil).GetResolutions), query)
+}
+
+// GetAgileBoards mocks base method
+func (m *MockyouTracker) GetAgileBoards() (map[string]model.AgileBoard, error) {
+	ret := m.ctrl.Call(m, "GetAgileBoards")
+	ret0, _ := ret[0].(map[string]model.AgileBoard)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAgileBoards indicates an expected call of GetAgileBoards
+func (mr *MockyouTrackerMockRecorder) GetAgileBoards() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAgileBoards", reflect.TypeOf((*MockyouTracker)(nil).GetAgileBoards))
+}
+
+// GetSprint mocks base method
+func (m *MockyouTracker) GetSprint(board model.AgileBoard, estimationField string) (model.Sprint, error) {
+	ret := m.ctrl.Call(m, "GetSprint", board, estimationField)
+	ret0, _ := ret[0].(model.Sprint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSprint indicates an expected call of GetSprint
+func (mr *MockyouTrackerMockRecorder) GetSprint(board, estimationField interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSprint", reflect.TypeOf((*MockyouTracker)(nil).GetSprint), board, estimationField)
+}
+
+// GetProjects mocks base method
+func (m *MockyouTracker) GetProjects() ([]model.Project, error) {
+	ret := m.ctrl.Call(m, "GetProjects")
+	ret0, _ := ret[0].([]model.Project)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProjects indicates an expected call of GetProjects
+func (mr *MockyouTrackerMockRecorder) GetProjects() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProjects", reflect.TypeOf((*MockyouTracker)(nil).GetProjects))
 }
 
 // Mockmetricser is a mock of metricser interface
@@ -70,23 +479,163 @@ func (m *Mockmetricser) EXPECT() *MockmetricserMockRecorder {
 }
 
 // EnableMonitoring mocks base method
-func (m *Mockmetricser) EnableMonitoring(queryName string, issue model.Issue) {
-	m.ctrl.Call(m, "EnableMonitoring", queryName, issue)
+func (m *Mockmetricser) EnableMonitoring(queryName, project string, issue model.Issue) {
+	m.ctrl.Call(m, "EnableMonitoring", queryName, project, issue)
 }
 
 // EnableMonitoring indicates an expected call of EnableMonitoring
-func (mr *MockmetricserMockRecorder) EnableMonitoring(queryName, issue interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMonitoring", reflect.TypeOf((*Mockmetricser)(nil).EnableMonitoring), queryName, issue)
+func (mr *MockmetricserMockRecorder) EnableMonitoring(queryName, project, issue interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMonitoring", reflect.TypeOf((*Mockmetricser)(nil).EnableMonitoring), queryName, project, issue)
 }
 
 // DisableMonitoring mocks base method
-func (m *Mockmetricser) DisableMonitoring(queryName string, issue model.Issue) {
-	m.ctrl.Call(m, "DisableMonitoring", queryName, issue)
+func (m *Mockmetricser) DisableMonitoring(queryName, project string, issue model.Issue) {
+	m.ctrl.Call(m, "DisableMonitoring", queryName, project, issue)
 }
 
 // DisableMonitoring indicates an expected call of DisableMonitoring
-func (mr *MockmetricserMockRecorder) DisableMonitoring(queryName, issue interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMonitoring", reflect.TypeOf((*Mockmetricser)(nil).DisableMonitoring), queryName, issue)
+func (mr *MockmetricserMockRecorder) DisableMonitoring(queryName, project, issue interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMonitoring", reflect.TypeOf((*Mockmetricser)(nil).DisableMonitoring), queryName, project, issue)
+}
+
+// EnableInfo mocks base method
+func (m *Mockmetricser) EnableInfo(queryName string, issue model.Issue) {
+	m.ctrl.Call(m, "EnableInfo", queryName, issue)
+}
+
+// EnableInfo indicates an expected call of EnableInfo
+func (mr *MockmetricserMockRecorder) EnableInfo(queryName, issue interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableInfo", reflect.TypeOf((*Mockmetricser)(nil).EnableInfo), queryName, issue)
+}
+
+// DisableInfo mocks base method
+func (m *Mockmetricser) DisableInfo(queryName string, issue model.Issue) {
+	m.ctrl.Call(m, "DisableInfo", queryName, issue)
+}
+
+// DisableInfo indicates an expected call of DisableInfo
+func (mr *MockmetricserMockRecorder) DisableInfo(queryName, issue interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableInfo", reflect.TypeOf((*Mockmetricser)(nil).DisableInfo), queryName, issue)
+}
+
+// SetSLABreached mocks base method
+func (m *Mockmetricser) SetSLABreached(queryName, project, issueID string, breached bool) {
+	m.ctrl.Call(m, "SetSLABreached", queryName, project, issueID, breached)
+}
+
+// SetSLABreached indicates an expected call of SetSLABreached
+func (mr *MockmetricserMockRecorder) SetSLABreached(queryName, project, issueID, breached interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSLABreached", reflect.TypeOf((*Mockmetricser)(nil).SetSLABreached), queryName, project, issueID, breached)
+}
+
+// SetSLA mocks base method
+func (m *Mockmetricser) SetSLA(queryName string, breachedCount int, timeToBreachSeconds float64) {
+	m.ctrl.Call(m, "SetSLA", queryName, breachedCount, timeToBreachSeconds)
+}
+
+// SetSLA indicates an expected call of SetSLA
+func (mr *MockmetricserMockRecorder) SetSLA(queryName, breachedCount, timeToBreachSeconds interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSLA", reflect.TypeOf((*Mockmetricser)(nil).SetSLA), queryName, breachedCount, timeToBreachSeconds)
+}
+
+// SetSpentMinutes mocks base method
+func (m *Mockmetricser) SetSpentMinutes(queryName string, spent model.SpentTime, minutes int) {
+	m.ctrl.Call(m, "SetSpentMinutes", queryName, spent, minutes)
+}
+
+// SetSpentMinutes indicates an expected call of SetSpentMinutes
+func (mr *MockmetricserMockRecorder) SetSpentMinutes(queryName, spent, minutes interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpentMinutes", reflect.TypeOf((*Mockmetricser)(nil).SetSpentMinutes), queryName, spent, minutes)
+}
+
+// AddSpentMinutes mocks base method
+func (m *Mockmetricser) AddSpentMinutes(queryName string, spent model.SpentTime, minutes int) {
+	m.ctrl.Call(m, "AddSpentMinutes", queryName, spent, minutes)
+}
+
+// AddSpentMinutes indicates an expected call of AddSpentMinutes
+func (mr *MockmetricserMockRecorder) AddSpentMinutes(queryName, spent, minutes interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSpentMinutes", reflect.TypeOf((*Mockmetricser)(nil).AddSpentMinutes), queryName, spent, minutes)
+}
+
+// SetEstimationMinutes mocks base method
+func (m *Mockmetricser) SetEstimationMinutes(queryName, project, issueID string, minutes int) {
+	m.ctrl.Call(m, "SetEstimationMinutes", queryName, project, issueID, minutes)
+}
+
+// SetEstimationMinutes indicates an expected call of SetEstimationMinutes
+func (mr *MockmetricserMockRecorder) SetEstimationMinutes(queryName, project, issueID, minutes interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEstimationMinutes", reflect.TypeOf((*Mockmetricser)(nil).SetEstimationMinutes), queryName, project, issueID, minutes)
+}
+
+// SetSprint mocks base method
+func (m *Mockmetricser) SetSprint(sprint model.Sprint) {
+	m.ctrl.Call(m, "SetSprint", sprint)
+}
+
+// SetSprint indicates an expected call of SetSprint
+func (mr *MockmetricserMockRecorder) SetSprint(sprint interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSprint", reflect.TypeOf((*Mockmetricser)(nil).SetSprint), sprint)
+}
+
+// ResetSprint mocks base method
+func (m *Mockmetricser) ResetSprint(sprint model.Sprint) {
+	m.ctrl.Call(m, "ResetSprint", sprint)
+}
+
+// ResetSprint indicates an expected call of ResetSprint
+func (mr *MockmetricserMockRecorder) ResetSprint(sprint interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetSprint", reflect.TypeOf((*Mockmetricser)(nil).ResetSprint), sprint)
+}
+
+// EnableProject mocks base method
+func (m *Mockmetricser) EnableProject(project model.Project) {
+	m.ctrl.Call(m, "EnableProject", project)
+}
+
+// EnableProject indicates an expected call of EnableProject
+func (mr *MockmetricserMockRecorder) EnableProject(project interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableProject", reflect.TypeOf((*Mockmetricser)(nil).EnableProject), project)
+}
+
+// DisableProject mocks base method
+func (m *Mockmetricser) DisableProject(project model.Project) {
+	m.ctrl.Call(m, "DisableProject", project)
+}
+
+// DisableProject indicates an expected call of DisableProject
+func (mr *MockmetricserMockRecorder) DisableProject(project interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableProject", reflect.TypeOf((*Mockmetricser)(nil).DisableProject), project)
+}
+
+// SetIssuesCount mocks base method
+func (m *Mockmetricser) SetIssuesCount(queryName, project string, count int) {
+	m.ctrl.Call(m, "SetIssuesCount", queryName, project, count)
+}
+
+// SetIssuesCount indicates an expected call of SetIssuesCount
+func (mr *MockmetricserMockRecorder) SetIssuesCount(queryName, project, count interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIssuesCount", reflect.TypeOf((*Mockmetricser)(nil).SetIssuesCount), queryName, project, count)
+}
+
+// SetQueryStatus mocks base method
+func (m *Mockmetricser) SetQueryStatus(queryName, severity string) {
+	m.ctrl.Call(m, "SetQueryStatus", queryName, severity)
+}
+
+// SetQueryStatus indicates an expected call of SetQueryStatus
+func (mr *MockmetricserMockRecorder) SetQueryStatus(queryName, severity interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetQueryStatus", reflect.TypeOf((*Mockmetricser)(nil).SetQueryStatus), queryName, severity)
+}
+
+// ObserveResolution mocks base method
+func (m *Mockmetricser) ObserveResolution(queryName, project string, seconds float64) {
+	m.ctrl.Call(m, "ObserveResolution", queryName, project, seconds)
+}
+
+// ObserveResolution indicates an expected call of ObserveResolution
+func (mr *MockmetricserMockRecorder) ObserveResolution(queryName, project, seconds interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveResolution", reflect.TypeOf((*Mockmetricser)(nil).ObserveResolution), queryName, project, seconds)
 }
 
 // ErrorInc mocks base method
@@ -98,3 +647,13 @@ func (m *Mockmetricser) ErrorInc(queryName string, err error) {
 func (mr *MockmetricserMockRecorder) ErrorInc(queryName, err interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ErrorInc", reflect.TypeOf((*Mockmetricser)(nil).ErrorInc), queryName, err)
 }
+
+// SprintErrorInc mocks base method
+func (m *Mockmetricser) SprintErrorInc(board string, err error) {
+	m.ctrl.Call(m, "SprintErrorInc", board, err)
+}
+
+// SprintErrorInc indicates an expected call of SprintErrorInc
+func (mr *MockmetricserMockRecorder) SprintErrorInc(board, err interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SprintErrorInc", reflect.TypeOf((*Mockmetricser)(nil).SprintErrorInc), board, err)
+}
@@ -1,11 +1,16 @@
 package monitoring
 
 import (
+	"bytes"
 	"errors"
 	"github.com/golang/mock/gomock"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
 	"github.com/stretchr/testify/assert"
+	"math"
 	"testing"
+	"time"
 )
 
 func TestNew(t *testing.T) {
@@ -14,37 +19,70 @@ func TestNew(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	issueser := NewMockgetIssueser(ctrl)
+	youTracker := NewMockyouTracker(ctrl)
 	metricser := NewMockmetricser(ctrl)
 
 	type testTableData struct {
-		queries  map[string]string
+		config   *config.Config
 		expected *Monitoring
 	}
 
 	testTable := []testTableData{
 		{
-			queries: map[string]string{
-				"test query 1": "#Unresolved",
-				"test query 2": "#Unassigned",
+			config: &config.Config{
+				Queries: map[string]config.Query{
+					"test query 1": {Query: "#Unresolved", Title: config.TitleLabel},
+					"test query 2": {Query: "#Unassigned", Title: config.TitleLabel},
+					"test query 3": {Type: config.TypeWorkItems, Query: "#Resolved", EstimationField: "Estimation"},
+				},
+				QueryTemplates: map[string]config.QueryTemplate{
+					"critical":   {Query: config.Query{Query: "project: {{.Project}} Priority: Critical"}, Projects: []string{"BE"}},
+					"unresolved": {Query: config.Query{Query: "project: {{.Project}} #Unresolved"}},
+				},
+				AgileBoards: []config.AgileBoard{
+					{Name: "Backend", EstimationField: "Estimation"},
+				},
+				ProjectDiscovery: &config.ProjectDiscovery{Query: "#Unresolved"},
 			},
 			expected: &Monitoring{
-				issueser:  issueser,
-				metricser: metricser,
+				lastHashes: make(map[string]string),
+				youTracker: youTracker,
+				metricser:  metricser,
 				lastActiveIssues: map[string]map[string]model.Issue{
 					"test query 1": {},
 					"test query 2": {},
 				},
-				queries: map[string]string{
-					"test query 1": "#Unresolved",
-					"test query 2": "#Unassigned",
+				lastSLAIssues: map[string]map[string]model.SLAIssue{},
+				lastTimeTracking: map[string]timeTracking{
+					"test query 3": newTimeTracking(),
+				},
+				lastResolutions: map[string]map[string]time.Time{},
+				lastSprints:     map[string]model.Sprint{},
+				lastProjects:    map[string]model.Project{},
+				queries: map[string]config.Query{
+					"test query 1": {Query: "#Unresolved", Title: config.TitleLabel},
+					"test query 2": {Query: "#Unassigned", Title: config.TitleLabel},
+					"test query 3": {Type: config.TypeWorkItems, Query: "#Resolved", EstimationField: "Estimation"},
 				},
+				discoveredQueries: map[string]config.Query{},
+				sources:           map[string]Source{},
+				templates: map[string]config.QueryTemplate{
+					"unresolved": {Query: config.Query{Query: "project: {{.Project}} #Unresolved"}},
+				},
+				boards: []config.AgileBoard{
+					{Name: "Backend", EstimationField: "Estimation"},
+				},
+				discovery: &config.ProjectDiscovery{Query: "#Unresolved"},
+				status:    map[string]QueryStatus{},
 			},
 		},
 	}
 
 	for _, testUnit := range testTable {
-		assert.Equal(t, testUnit.expected, New(issueser, metricser, testUnit.queries))
+		monitoring := New(youTracker, metricser, testUnit.config, nil)
+		assert.NotNil(t, monitoring.now)
+		monitoring.now = nil
+		assert.Equal(t, testUnit.expected, monitoring)
 	}
 }
 
@@ -57,8 +95,8 @@ func TestMonitoring_RefreshMetrics(t *testing.T) {
 	type testTableData struct {
 		tcase                    string
 		lastActiveIssues         map[string]map[string]model.Issue
-		queries                  map[string]string
-		expectFunc               func(i *MockgetIssueser, m *Mockmetricser)
+		queries                  map[string]config.Query
+		expectFunc               func(i *MockyouTracker, m *Mockmetricser)
 		expectedLastActiveIssues map[string]map[string]model.Issue
 	}
 
@@ -83,32 +121,34 @@ func TestMonitoring_RefreshMetrics(t *testing.T) {
 					},
 				},
 			},
-			queries: map[string]string{
-				"test query 1": "#Unresolved",
-				"test query 2": "#Unassigned",
+			queries: map[string]config.Query{
+				"test query 1": {Query: "#Unresolved", Title: config.TitleLabel},
+				"test query 2": {Query: "#Unassigned", Title: config.TitleLabel},
 			},
-			expectFunc: func(i *MockgetIssueser, m *Mockmetricser) {
+			expectFunc: func(i *MockyouTracker, m *Mockmetricser) {
 				// test query 1
-				i.EXPECT().GetIssues("#Unresolved").Return(
+				i.EXPECT().GetIssuesIfChanged("#Unresolved", gomock.Any()).Return(
 					map[string]model.Issue{
 						"YT-101 New": {
 							ID:    "YT-101",
 							Title: "New",
 						},
 					},
+					"hash",
+					true,
 					nil,
 				)
-				m.EXPECT().DisableMonitoring("test query 1", model.Issue{
+				m.EXPECT().DisableMonitoring("test query 1", "", model.Issue{
 					ID:    "YT-100",
 					Title: "For disable",
 				})
-				m.EXPECT().EnableMonitoring("test query 1", model.Issue{
+				m.EXPECT().EnableMonitoring("test query 1", "", model.Issue{
 					ID:    "YT-101",
 					Title: "New",
 				})
 
 				// test query 2
-				i.EXPECT().GetIssues("#Unassigned").Return(
+				i.EXPECT().GetIssuesIfChanged("#Unassigned", gomock.Any()).Return(
 					map[string]model.Issue{
 						"YT-200 Not changed": {
 							ID:    "YT-200",
@@ -119,13 +159,15 @@ func TestMonitoring_RefreshMetrics(t *testing.T) {
 							Title: "New name",
 						},
 					},
+					"hash",
+					true,
 					nil,
 				)
-				m.EXPECT().DisableMonitoring("test query 2", model.Issue{
+				m.EXPECT().DisableMonitoring("test query 2", "", model.Issue{
 					ID:    "YT-300",
 					Title: "Renamed",
 				})
-				m.EXPECT().EnableMonitoring("test query 2", model.Issue{
+				m.EXPECT().EnableMonitoring("test query 2", "", model.Issue{
 					ID:    "YT-300",
 					Title: "New name",
 				})
@@ -149,6 +191,85 @@ func TestMonitoring_RefreshMetrics(t *testing.T) {
 				},
 			},
 		},
+		{
+			tcase: "title modes and url label",
+			lastActiveIssues: map[string]map[string]model.Issue{
+				"test query 1": {
+					"YT-100 For disable": model.Issue{
+						ID:    "YT-100",
+						Title: "For disable",
+						URL:   "http://www.test.com/issue/YT-100",
+					},
+				},
+				"test query 2": {},
+			},
+			queries: map[string]config.Query{
+				"test query 1": {Query: "#Unresolved", Title: config.TitleInfo, TitleMaxLength: 3},
+				"test query 2": {Query: "#Unassigned", Title: config.TitleNone, URLLabel: true},
+			},
+			expectFunc: func(i *MockyouTracker, m *Mockmetricser) {
+				// test query 1
+				i.EXPECT().GetIssuesIfChanged("#Unresolved", gomock.Any()).Return(
+					map[string]model.Issue{
+						"YT-101 Новая": {
+							ID:    "YT-101",
+							Title: "Новая",
+						},
+					},
+					"hash",
+					true,
+					nil,
+				)
+				m.EXPECT().DisableInfo("test query 1", model.Issue{
+					ID:    "YT-100",
+					Title: "For",
+					URL:   "http://www.test.com/issue/YT-100",
+				})
+				m.EXPECT().DisableMonitoring("test query 1", "", model.Issue{
+					ID: "YT-100",
+				})
+				m.EXPECT().EnableInfo("test query 1", model.Issue{
+					ID:    "YT-101",
+					Title: "Нов",
+				})
+				m.EXPECT().EnableMonitoring("test query 1", "", model.Issue{
+					ID: "YT-101",
+				})
+
+				// test query 2
+				i.EXPECT().GetIssuesIfChanged("#Unassigned", gomock.Any()).Return(
+					map[string]model.Issue{
+						"YT-200 New": {
+							ID:    "YT-200",
+							Title: "New",
+							URL:   "http://www.test.com/issue/YT-200",
+						},
+					},
+					"hash",
+					true,
+					nil,
+				)
+				m.EXPECT().EnableMonitoring("test query 2", "", model.Issue{
+					ID:  "YT-200",
+					URL: "http://www.test.com/issue/YT-200",
+				})
+			},
+			expectedLastActiveIssues: map[string]map[string]model.Issue{
+				"test query 1": {
+					"YT-101 Новая": model.Issue{
+						ID:    "YT-101",
+						Title: "Новая",
+					},
+				},
+				"test query 2": {
+					"YT-200 New": model.Issue{
+						ID:    "YT-200",
+						Title: "New",
+						URL:   "http://www.test.com/issue/YT-200",
+					},
+				},
+			},
+		},
 		{
 			tcase: "get issues error",
 			lastActiveIssues: map[string]map[string]model.Issue{
@@ -169,14 +290,14 @@ func TestMonitoring_RefreshMetrics(t *testing.T) {
 					},
 				},
 			},
-			queries: map[string]string{
-				"test query 1": "#Unresolved",
-				"test query 2": "#Unassigned",
+			queries: map[string]config.Query{
+				"test query 1": {Query: "#Unresolved", Title: config.TitleLabel},
+				"test query 2": {Query: "#Unassigned", Title: config.TitleLabel},
 			},
-			expectFunc: func(i *MockgetIssueser, m *Mockmetricser) {
-				i.EXPECT().GetIssues("#Unresolved").Return(nil, errors.New("test query 1 error"))
+			expectFunc: func(i *MockyouTracker, m *Mockmetricser) {
+				i.EXPECT().GetIssuesIfChanged("#Unresolved", gomock.Any()).Return(nil, "", false, errors.New("test query 1 error"))
 				m.EXPECT().ErrorInc("test query 1", errors.New("test query 1 error"))
-				i.EXPECT().GetIssues("#Unassigned").Return(nil, errors.New("test query 2 error"))
+				i.EXPECT().GetIssuesIfChanged("#Unassigned", gomock.Any()).Return(nil, "", false, errors.New("test query 2 error"))
 				m.EXPECT().ErrorInc("test query 2", errors.New("test query 2 error"))
 			},
 			expectedLastActiveIssues: map[string]map[string]model.Issue{
@@ -201,19 +322,914 @@ func TestMonitoring_RefreshMetrics(t *testing.T) {
 	}
 
 	for _, testUnit := range testTable {
-		issueser := NewMockgetIssueser(ctrl)
+		youTracker := NewMockyouTracker(ctrl)
 		metricser := NewMockmetricser(ctrl)
 
 		monitoring := &Monitoring{
-			issueser:         issueser,
+			lastHashes:       make(map[string]string),
+			youTracker:       youTracker,
+			sources:          map[string]Source{config.SourceYouTrack: youTracker},
 			metricser:        metricser,
 			lastActiveIssues: testUnit.lastActiveIssues,
 			queries:          testUnit.queries,
 		}
 
-		testUnit.expectFunc(issueser, metricser)
+		testUnit.expectFunc(youTracker, metricser)
 		monitoring.RefreshMetrics()
 
 		assert.Equal(t, testUnit.expectedLastActiveIssues, monitoring.lastActiveIssues, testUnit.tcase)
 	}
 }
+
+func TestMonitoring_RefreshMetrics_TimeTracking(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queries := map[string]config.Query{
+		"time": {Type: config.TypeWorkItems, Query: "#Resolved", EstimationField: "Estimation"},
+	}
+	now := time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type testTableData struct {
+		tcase                    string
+		lastTimeTracking         timeTracking
+		expectFunc               func(tt *MockyouTracker, m *Mockmetricser)
+		expectedLastTimeTracking timeTracking
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "1 new work item, 1 increased work item, 1 gone issue",
+			lastTimeTracking: timeTracking{
+				spent: map[model.SpentTime]int{
+					{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}: 60,
+					{IssueID: "YT-200", Project: "YT", Author: "jane", Type: "Testing"}:     30,
+				},
+				estimation: map[string]model.TimeTracking{
+					"YT-100": {IssueID: "YT-100", Project: "YT", EstimationMinutes: 120},
+					"YT-200": {IssueID: "YT-200", Project: "YT", EstimationMinutes: 60},
+				},
+				workItems: map[string]workItem{
+					"1-1": {minutes: 60, seen: now.Add(-time.Hour)},
+					"2-1": {minutes: 30, seen: now.Add(-time.Hour)},
+				},
+			},
+			expectFunc: func(tt *MockyouTracker, m *Mockmetricser) {
+				tt.EXPECT().GetTimeTracking("#Resolved", "Estimation").Return(
+					[]model.TimeTracking{
+						{
+							IssueID:           "YT-100",
+							Project:           "YT",
+							EstimationMinutes: 240,
+							WorkItems: []model.WorkItem{
+								{ID: "1-1", Author: "john", Type: "Development", Minutes: 90},
+								{ID: "1-2", Author: "john", Type: "Development", Minutes: 15},
+							},
+						},
+					},
+					nil,
+				)
+
+				spent := model.SpentTime{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}
+				m.EXPECT().AddSpentMinutes("time", spent, 30)
+				m.EXPECT().AddSpentMinutes("time", spent, 15)
+				m.EXPECT().SetSpentMinutes("time", spent, 105)
+				m.EXPECT().SetSpentMinutes("time", model.SpentTime{IssueID: "YT-200", Project: "YT", Author: "jane", Type: "Testing"}, 0)
+				m.EXPECT().SetEstimationMinutes("time", "YT", "YT-100", 240)
+				m.EXPECT().SetEstimationMinutes("time", "YT", "YT-200", 0)
+			},
+			expectedLastTimeTracking: timeTracking{
+				spent: map[model.SpentTime]int{
+					{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}: 105,
+				},
+				estimation: map[string]model.TimeTracking{
+					"YT-100": {
+						IssueID:           "YT-100",
+						Project:           "YT",
+						EstimationMinutes: 240,
+						WorkItems: []model.WorkItem{
+							{ID: "1-1", Author: "john", Type: "Development", Minutes: 90},
+							{ID: "1-2", Author: "john", Type: "Development", Minutes: 15},
+						},
+					},
+				},
+				workItems: map[string]workItem{
+					"1-1": {minutes: 90, seen: now},
+					"1-2": {minutes: 15, seen: now},
+					"2-1": {minutes: 30, seen: now.Add(-time.Hour)},
+				},
+			},
+		},
+		{
+			tcase: "returned issue work items are not counted again, expired work item is forgotten",
+			lastTimeTracking: timeTracking{
+				spent:      map[model.SpentTime]int{},
+				estimation: map[string]model.TimeTracking{},
+				workItems: map[string]workItem{
+					"2-1": {minutes: 30, seen: now.Add(-24 * time.Hour)},
+					"3-1": {minutes: 10, seen: now.Add(-workItemRetention)},
+				},
+			},
+			expectFunc: func(tt *MockyouTracker, m *Mockmetricser) {
+				tt.EXPECT().GetTimeTracking("#Resolved", "Estimation").Return(
+					[]model.TimeTracking{
+						{
+							IssueID:           "YT-200",
+							Project:           "YT",
+							EstimationMinutes: 60,
+							WorkItems:         []model.WorkItem{{ID: "2-1", Author: "jane", Type: "Testing", Minutes: 30}},
+						},
+					},
+					nil,
+				)
+
+				m.EXPECT().SetSpentMinutes("time", model.SpentTime{IssueID: "YT-200", Project: "YT", Author: "jane", Type: "Testing"}, 30)
+				m.EXPECT().SetEstimationMinutes("time", "YT", "YT-200", 60)
+			},
+			expectedLastTimeTracking: timeTracking{
+				spent: map[model.SpentTime]int{
+					{IssueID: "YT-200", Project: "YT", Author: "jane", Type: "Testing"}: 30,
+				},
+				estimation: map[string]model.TimeTracking{
+					"YT-200": {
+						IssueID:           "YT-200",
+						Project:           "YT",
+						EstimationMinutes: 60,
+						WorkItems:         []model.WorkItem{{ID: "2-1", Author: "jane", Type: "Testing", Minutes: 30}},
+					},
+				},
+				workItems: map[string]workItem{"2-1": {minutes: 30, seen: now}},
+			},
+		},
+		{
+			tcase:            "first refresh seeds work items",
+			lastTimeTracking: newTimeTracking(),
+			expectFunc: func(tt *MockyouTracker, m *Mockmetricser) {
+				tt.EXPECT().GetTimeTracking("#Resolved", "Estimation").Return(
+					[]model.TimeTracking{
+						{
+							IssueID:           "YT-100",
+							Project:           "YT",
+							EstimationMinutes: 240,
+							WorkItems:         []model.WorkItem{{ID: "1-1", Author: "john", Type: "Development", Minutes: 90}},
+						},
+					},
+					nil,
+				)
+
+				m.EXPECT().SetSpentMinutes("time", model.SpentTime{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}, 90)
+				m.EXPECT().SetEstimationMinutes("time", "YT", "YT-100", 240)
+			},
+			expectedLastTimeTracking: timeTracking{
+				spent: map[model.SpentTime]int{
+					{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}: 90,
+				},
+				estimation: map[string]model.TimeTracking{
+					"YT-100": {
+						IssueID:           "YT-100",
+						Project:           "YT",
+						EstimationMinutes: 240,
+						WorkItems:         []model.WorkItem{{ID: "1-1", Author: "john", Type: "Development", Minutes: 90}},
+					},
+				},
+				workItems: map[string]workItem{"1-1": {minutes: 90, seen: now}},
+			},
+		},
+		{
+			tcase:            "get time tracking error",
+			lastTimeTracking: newTimeTracking(),
+			expectFunc: func(tt *MockyouTracker, m *Mockmetricser) {
+				tt.EXPECT().GetTimeTracking("#Resolved", "Estimation").Return(nil, errors.New("time tracking error"))
+				m.EXPECT().ErrorInc("time", errors.New("time tracking error"))
+			},
+			expectedLastTimeTracking: newTimeTracking(),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		youTracker := NewMockyouTracker(ctrl)
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes:       make(map[string]string),
+			youTracker:       youTracker,
+			sources:          map[string]Source{config.SourceYouTrack: youTracker},
+			metricser:        metricser,
+			lastTimeTracking: map[string]timeTracking{"time": testUnit.lastTimeTracking},
+			queries:          queries,
+			now:              func() time.Time { return now },
+		}
+
+		testUnit.expectFunc(youTracker, metricser)
+		monitoring.RefreshMetrics()
+
+		assert.Equal(t, testUnit.expectedLastTimeTracking, monitoring.lastTimeTracking["time"], testUnit.tcase)
+	}
+}
+
+func TestMonitoring_RefreshMetrics_Sprint(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	boards := []config.AgileBoard{
+		{Name: "Backend", EstimationField: "Estimation"},
+	}
+	backend := model.AgileBoard{ID: "108-1", Name: "Backend", CurrentSprintID: "109-5"}
+	agiles := map[string]model.AgileBoard{"Backend": backend}
+
+	sprint5 := model.Sprint{
+		Board:                      "Backend",
+		Name:                       "Sprint 5",
+		Columns:                    map[string]int{"Open": 1, "Review": 2, "Done": 3},
+		RemainingEstimationMinutes: 60,
+	}
+	sprint5Changed := model.Sprint{
+		Board:                      "Backend",
+		Name:                       "Sprint 5",
+		Columns:                    map[string]int{"Open": 0, "Done": 6},
+		RemainingEstimationMinutes: 0,
+	}
+	sprint6 := model.Sprint{
+		Board:                      "Backend",
+		Name:                       "Sprint 6",
+		Columns:                    map[string]int{"Open": 5, "Done": 0},
+		RemainingEstimationMinutes: 300,
+	}
+
+	type testTableData struct {
+		tcase               string
+		lastSprints         map[string]model.Sprint
+		expectFunc          func(yt *MockyouTracker, m *Mockmetricser)
+		expectedLastSprints map[string]model.Sprint
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:       "first refresh",
+			lastSprints: map[string]model.Sprint{},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetAgileBoards().Return(agiles, nil)
+				yt.EXPECT().GetSprint(backend, "Estimation").Return(sprint5, nil)
+				m.EXPECT().SetSprint(sprint5)
+			},
+			expectedLastSprints: map[string]model.Sprint{"Backend": sprint5},
+		},
+		{
+			tcase:       "column removed",
+			lastSprints: map[string]model.Sprint{"Backend": sprint5},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetAgileBoards().Return(agiles, nil)
+				yt.EXPECT().GetSprint(backend, "Estimation").Return(sprint5Changed, nil)
+				m.EXPECT().ResetSprint(model.Sprint{
+					Board:                      "Backend",
+					Name:                       "Sprint 5",
+					Columns:                    map[string]int{"Review": 2},
+					RemainingEstimationMinutes: 60,
+				})
+				m.EXPECT().SetSprint(sprint5Changed)
+			},
+			expectedLastSprints: map[string]model.Sprint{"Backend": sprint5Changed},
+		},
+		{
+			tcase:       "sprint changed",
+			lastSprints: map[string]model.Sprint{"Backend": sprint5},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetAgileBoards().Return(agiles, nil)
+				yt.EXPECT().GetSprint(backend, "Estimation").Return(sprint6, nil)
+				m.EXPECT().ResetSprint(sprint5)
+				m.EXPECT().SetSprint(sprint6)
+			},
+			expectedLastSprints: map[string]model.Sprint{"Backend": sprint6},
+		},
+		{
+			tcase:       "get sprint error",
+			lastSprints: map[string]model.Sprint{"Backend": sprint5},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetAgileBoards().Return(agiles, nil)
+				yt.EXPECT().GetSprint(backend, "Estimation").Return(model.Sprint{}, errors.New("sprint error"))
+				m.EXPECT().SprintErrorInc("Backend", errors.New("sprint error"))
+			},
+			expectedLastSprints: map[string]model.Sprint{"Backend": sprint5},
+		},
+		{
+			tcase:       "get agile boards error",
+			lastSprints: map[string]model.Sprint{"Backend": sprint5},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetAgileBoards().Return(nil, errors.New("agiles error"))
+				m.EXPECT().SprintErrorInc("Backend", errors.New("agiles error"))
+			},
+			expectedLastSprints: map[string]model.Sprint{"Backend": sprint5},
+		},
+		{
+			tcase:       "board not found",
+			lastSprints: map[string]model.Sprint{},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetAgileBoards().Return(map[string]model.AgileBoard{}, nil)
+				m.EXPECT().SprintErrorInc("Backend", errors.New("agile board not found: Backend"))
+			},
+			expectedLastSprints: map[string]model.Sprint{},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		youTracker := NewMockyouTracker(ctrl)
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes:  make(map[string]string),
+			youTracker:  youTracker,
+			metricser:   metricser,
+			lastSprints: testUnit.lastSprints,
+			boards:      boards,
+		}
+
+		testUnit.expectFunc(youTracker, metricser)
+		monitoring.RefreshMetrics()
+
+		assert.Equal(t, testUnit.expectedLastSprints, monitoring.lastSprints, testUnit.tcase)
+	}
+}
+
+func TestMonitoring_RefreshMetrics_SprintBoards(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	youTracker := NewMockyouTracker(ctrl)
+	metricser := NewMockmetricser(ctrl)
+
+	monitoring := &Monitoring{
+		youTracker:  youTracker,
+		metricser:   metricser,
+		lastSprints: make(map[string]model.Sprint),
+		boards: []config.AgileBoard{
+			{Name: "Backend", EstimationField: "Estimation"},
+			{Name: "Frontend", EstimationField: "Estimation"},
+		},
+	}
+
+	backend := model.AgileBoard{ID: "108-1", Name: "Backend", CurrentSprintID: "109-5"}
+	frontend := model.AgileBoard{ID: "108-2", Name: "Frontend", CurrentSprintID: "109-6"}
+	backendSprint := model.Sprint{Board: "Backend", Name: "Sprint 5"}
+	frontendSprint := model.Sprint{Board: "Frontend", Name: "Sprint 6"}
+
+	// agile boards are got once for all boards
+	youTracker.EXPECT().GetAgileBoards().Return(map[string]model.AgileBoard{"Backend": backend, "Frontend": frontend}, nil)
+	youTracker.EXPECT().GetSprint(backend, "Estimation").Return(backendSprint, nil)
+	youTracker.EXPECT().GetSprint(frontend, "Estimation").Return(frontendSprint, nil)
+	metricser.EXPECT().SetSprint(backendSprint)
+	metricser.EXPECT().SetSprint(frontendSprint)
+
+	assert.Equal(t, 0, monitoring.RefreshMetrics())
+}
+
+func TestMonitoring_RefreshMetrics_SLA(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queries := map[string]config.Query{
+		"critical": {
+			Type:  config.TypeIssues,
+			Query: "Priority: Critical",
+			Title: config.TitleNone,
+			SLA:   &config.SLA{ThresholdSeconds: 7200, StartField: "Reopened"},
+		},
+	}
+	now := time.Date(2019, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	breached := model.SLAIssue{Issue: model.Issue{ID: "BE-1", Title: "Breached"}, Project: "BE", Start: now.Add(-3 * time.Hour)}
+	inTime := model.SLAIssue{Issue: model.Issue{ID: "BE-2", Title: "In time"}, Project: "BE", Start: now.Add(-time.Hour)}
+	notStarted := model.SLAIssue{Issue: model.Issue{ID: "BE-3", Title: "Not started"}, Project: "BE"}
+	removed := model.SLAIssue{Issue: model.Issue{ID: "FE-1", Title: "Removed"}, Project: "FE", Start: now.Add(-3 * time.Hour)}
+
+	type testTableData struct {
+		tcase                 string
+		lastActiveIssues      map[string]map[string]model.Issue
+		lastSLAIssues         map[string]map[string]model.SLAIssue
+		expectFunc            func(yt *MockyouTracker, m *Mockmetricser)
+		expectedLastSLAIssues map[string]map[string]model.SLAIssue
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "breached, in time, not started and removed issues",
+			lastActiveIssues: map[string]map[string]model.Issue{
+				"critical": {"FE-1 Removed": removed.Issue},
+			},
+			lastSLAIssues: map[string]map[string]model.SLAIssue{
+				"critical": {"FE-1 Removed": removed},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetSLAIssues("Priority: Critical", "Reopened").Return(map[string]model.SLAIssue{
+					"BE-1 Breached":    breached,
+					"BE-2 In time":     inTime,
+					"BE-3 Not started": notStarted,
+				}, nil)
+				m.EXPECT().DisableMonitoring("critical", "", model.Issue{ID: "FE-1"})
+				m.EXPECT().EnableMonitoring("critical", "", model.Issue{ID: "BE-1"})
+				m.EXPECT().EnableMonitoring("critical", "", model.Issue{ID: "BE-2"})
+				m.EXPECT().EnableMonitoring("critical", "", model.Issue{ID: "BE-3"})
+				m.EXPECT().SetSLABreached("critical", "FE", "FE-1", false)
+				m.EXPECT().SetSLABreached("critical", "BE", "BE-1", true)
+				m.EXPECT().SetSLABreached("critical", "BE", "BE-2", false)
+				m.EXPECT().SetSLA("critical", 1, float64(3600))
+			},
+			expectedLastSLAIssues: map[string]map[string]model.SLAIssue{
+				"critical": {"BE-1 Breached": breached, "BE-2 In time": inTime},
+			},
+		},
+		{
+			tcase:            "no issues",
+			lastActiveIssues: map[string]map[string]model.Issue{},
+			lastSLAIssues:    map[string]map[string]model.SLAIssue{},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetSLAIssues("Priority: Critical", "Reopened").Return(map[string]model.SLAIssue{}, nil)
+				m.EXPECT().SetSLA("critical", 0, math.Inf(1))
+			},
+			expectedLastSLAIssues: map[string]map[string]model.SLAIssue{
+				"critical": {},
+			},
+		},
+		{
+			tcase:            "get sla issues error",
+			lastActiveIssues: map[string]map[string]model.Issue{},
+			lastSLAIssues:    map[string]map[string]model.SLAIssue{},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetSLAIssues("Priority: Critical", "Reopened").Return(nil, errors.New("get sla issues error"))
+				m.EXPECT().ErrorInc("critical", errors.New("get sla issues error"))
+			},
+			expectedLastSLAIssues: map[string]map[string]model.SLAIssue{},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		youTracker := NewMockyouTracker(ctrl)
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes:       make(map[string]string),
+			youTracker:       youTracker,
+			sources:          map[string]Source{config.SourceYouTrack: youTracker},
+			metricser:        metricser,
+			lastActiveIssues: testUnit.lastActiveIssues,
+			lastSLAIssues:    testUnit.lastSLAIssues,
+			queries:          queries,
+			now:              func() time.Time { return now },
+		}
+
+		testUnit.expectFunc(youTracker, metricser)
+		monitoring.RefreshMetrics()
+
+		assert.Equal(t, testUnit.expectedLastSLAIssues, monitoring.lastSLAIssues, testUnit.tcase)
+	}
+}
+
+func TestMonitoring_RefreshMetrics_Source(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queries := map[string]config.Query{
+		"offline": {Source: config.SourceFile, Type: config.TypeIssues, Query: "/data/issues.csv", Title: config.TitleLabel},
+		"count":   {Source: config.SourceFile, Type: config.TypeCount, Query: "/data/issues.json"},
+		"online":  {Source: config.SourceYouTrack, Type: config.TypeCount, Query: "#Unresolved"},
+	}
+
+	youTracker := NewMockyouTracker(ctrl)
+	metricser := NewMockmetricser(ctrl)
+	source := NewMockSource(ctrl)
+
+	monitoring := New(youTracker, metricser, &config.Config{Queries: queries}, nil)
+	monitoring.RegisterSource(config.SourceYouTrack, youTracker)
+	monitoring.RegisterSource(config.SourceFile, source)
+
+	source.EXPECT().GetIssues("/data/issues.csv").Return(map[string]model.Issue{"OFF-1 First": {ID: "OFF-1", Title: "First"}}, nil)
+	metricser.EXPECT().EnableMonitoring("offline", "", model.Issue{ID: "OFF-1", Title: "First"})
+	source.EXPECT().GetIssues("/data/issues.json").Return(nil, errors.New("read error"))
+	metricser.EXPECT().ErrorInc("count", errors.New("read error"))
+	youTracker.EXPECT().GetIssuesCount("#Unresolved").Return(0, nil)
+	metricser.EXPECT().SetIssuesCount("online", "", 0)
+
+	monitoring.RefreshMetrics()
+
+	// not registered source
+	monitoring = New(youTracker, metricser, &config.Config{Queries: map[string]config.Query{"count": queries["count"]}}, nil)
+	metricser.EXPECT().ErrorInc("count", errors.New("source is not registered: file"))
+
+	monitoring.RefreshMetrics()
+}
+
+func TestMonitoring_RefreshMetrics_NotModified(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queries := map[string]config.Query{
+		"issues": {Type: config.TypeIssues, Query: "#Unresolved", Title: config.TitleLabel},
+	}
+
+	youTracker := NewMockyouTracker(ctrl)
+	metricser := NewMockmetricser(ctrl)
+	monitoring := New(youTracker, metricser, &config.Config{Queries: queries}, nil)
+	monitoring.RegisterSource(config.SourceYouTrack, youTracker)
+
+	// first refresh
+	youTracker.EXPECT().GetIssuesIfChanged("#Unresolved", "").Return(map[string]model.Issue{"YT-1 First": {ID: "YT-1", Title: "First"}}, "issues hash", true, nil)
+	metricser.EXPECT().EnableMonitoring("issues", "", model.Issue{ID: "YT-1", Title: "First"})
+
+	monitoring.RefreshMetrics()
+
+	// responses are not changed, metrics are not touched
+	youTracker.EXPECT().GetIssuesIfChanged("#Unresolved", "issues hash").Return(nil, "issues hash", false, nil)
+
+	monitoring.RefreshMetrics()
+
+	assert.Equal(t, map[string]string{"issues": "issues hash"}, monitoring.lastHashes)
+	assert.Equal(t, map[string]model.Issue{"YT-1 First": {ID: "YT-1", Title: "First"}}, monitoring.lastActiveIssues["issues"])
+}
+
+func TestMonitoring_RefreshMetrics_Logging(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	buf := &bytes.Buffer{}
+	log, err := logging.New(buf, logging.FormatLogfmt, logging.LevelInfo)
+	assert.NoError(t, err)
+
+	youTracker := NewMockyouTracker(ctrl)
+	metricser := NewMockmetricser(ctrl)
+	monitoring := New(youTracker, metricser, &config.Config{Queries: map[string]config.Query{
+		"issues": {Type: config.TypeIssues, Query: "#Unassigned", Title: config.TitleLabel},
+	}}, log)
+	monitoring.RegisterSource(config.SourceYouTrack, youTracker)
+
+	youTracker.EXPECT().GetIssuesIfChanged("#Unassigned", "").Return(map[string]model.Issue{"YT-1 First": {ID: "YT-1", Title: "First"}}, "hash", true, nil)
+	metricser.EXPECT().EnableMonitoring("issues", "", model.Issue{ID: "YT-1", Title: "First"})
+	assert.Equal(t, 0, monitoring.RefreshMetrics())
+	assert.Regexp(t, `level=info msg="refresh finished" query=issues duration=\S+ size=1\n$`, buf.String())
+
+	youTracker.EXPECT().GetIssuesIfChanged("#Unassigned", "hash").Return(nil, "hash", false, nil)
+	assert.Equal(t, 0, monitoring.RefreshMetrics())
+	assert.Regexp(t, `level=info msg="refresh skipped, response not modified" query=issues duration=\S+\n$`, buf.String())
+
+	youTracker.EXPECT().GetIssuesIfChanged("#Unassigned", "hash").Return(nil, "", false, errors.New("request error"))
+	metricser.EXPECT().ErrorInc("issues", errors.New("request error"))
+	assert.Equal(t, 1, monitoring.RefreshMetrics())
+	assert.Regexp(t, `level=error msg="refresh failed" query=issues duration=\S+ error="request error"\n$`, buf.String())
+}
+
+func TestMonitoring_RefreshQuery(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queries := map[string]config.Query{
+		"issues": {
+			Type: config.TypeIssues, Query: "#Unresolved", Project: "YT", Title: config.TitleInfo,
+			Thresholds: &config.Thresholds{Warning: &config.Threshold{Count: 1}},
+		},
+		"count":  {Type: config.TypeCount, Query: "#Unresolved", Project: "YT"},
+		"failed": {Type: config.TypeCount, Query: "#Resolved", Project: "YT"},
+	}
+
+	youTracker := NewMockyouTracker(ctrl)
+	metricser := NewMockmetricser(ctrl)
+	monitoring := New(youTracker, metricser, &config.Config{Queries: queries}, nil)
+	monitoring.RegisterSource(config.SourceYouTrack, youTracker)
+
+	issues := map[string]model.Issue{
+		"YT-1 First": {ID: "YT-1", Title: "First", URL: "https://www.test.com/issue/YT-1"},
+	}
+
+	type testTableData struct {
+		queryName      string
+		expectFunc     func(y *MockyouTracker, m *Mockmetricser)
+		expectedCount  int
+		expectedIssues map[string]model.Issue
+		expectedErr    error
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "issues",
+			expectFunc: func(y *MockyouTracker, m *Mockmetricser) {
+				y.EXPECT().GetIssuesIfChanged("#Unresolved", "").Return(issues, "hash", true, nil)
+				m.EXPECT().EnableInfo("issues", model.Issue{ID: "YT-1", Title: "First", URL: "https://www.test.com/issue/YT-1"})
+				m.EXPECT().EnableMonitoring("issues", "YT", model.Issue{ID: "YT-1"})
+				m.EXPECT().SetQueryStatus("issues", model.SeverityWarning)
+			},
+			expectedCount:  1,
+			expectedIssues: issues,
+			expectedErr:    nil,
+		},
+		{
+			queryName: "count",
+			expectFunc: func(y *MockyouTracker, m *Mockmetricser) {
+				y.EXPECT().GetIssuesCount("#Unresolved").Return(5, nil)
+				m.EXPECT().SetIssuesCount("count", "YT", 5)
+			},
+			expectedCount:  5,
+			expectedIssues: map[string]model.Issue{},
+			expectedErr:    nil,
+		},
+		{
+			queryName: "failed",
+			expectFunc: func(y *MockyouTracker, m *Mockmetricser) {
+				y.EXPECT().GetIssuesCount("#Resolved").Return(0, errors.New("request error"))
+			},
+			expectedCount:  0,
+			expectedIssues: nil,
+			expectedErr:    errors.New("request error"),
+		},
+		{
+			queryName:      "unknown",
+			expectFunc:     func(y *MockyouTracker, m *Mockmetricser) {},
+			expectedCount:  0,
+			expectedIssues: nil,
+			expectedErr:    errors.New("query is not configured: unknown"),
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(youTracker, metricser)
+		count, issues, err := monitoring.RefreshQuery(testUnit.queryName)
+		assert.Equal(t, testUnit.expectedCount, count, testUnit.queryName)
+		assert.Equal(t, testUnit.expectedIssues, issues, testUnit.queryName)
+		assert.Equal(t, testUnit.expectedErr, err, testUnit.queryName)
+	}
+}
+
+func TestMonitoring_RefreshMetrics_Count(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queries := map[string]config.Query{
+		"critical": {Type: config.TypeCount, Query: "Priority: Critical", Project: "BE"},
+	}
+
+	type testTableData struct {
+		tcase      string
+		expectFunc func(yt *MockyouTracker, m *Mockmetricser)
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "success",
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetIssuesCount("Priority: Critical").Return(2, nil)
+				m.EXPECT().SetIssuesCount("critical", "BE", 2)
+			},
+		},
+		{
+			tcase: "get issues count error",
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetIssuesCount("Priority: Critical").Return(0, errors.New("get issues count error"))
+				m.EXPECT().ErrorInc("critical", errors.New("get issues count error"))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		youTracker := NewMockyouTracker(ctrl)
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes: make(map[string]string),
+			youTracker: youTracker,
+			sources:    map[string]Source{config.SourceYouTrack: youTracker},
+			metricser:  metricser,
+			queries:    queries,
+		}
+
+		testUnit.expectFunc(youTracker, metricser)
+		monitoring.RefreshMetrics()
+	}
+}
+
+func TestMonitoring_RefreshMetrics_Resolution(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queries := map[string]config.Query{
+		"bugs": {Type: config.TypeResolution, Query: "Type: Bug", ResolutionWindowSeconds: 86400},
+	}
+	now := time.Date(2019, 1, 10, 12, 0, 0, 0, time.UTC)
+	const expectedQuery = "Type: Bug resolved date: 2019-01-08 .. Today"
+
+	type testTableData struct {
+		tcase                   string
+		lastResolutions         map[string]map[string]time.Time
+		expectFunc              func(yt *MockyouTracker, m *Mockmetricser)
+		expectedLastResolutions map[string]map[string]time.Time
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:           "first refresh",
+			lastResolutions: map[string]map[string]time.Time{},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetResolutions(expectedQuery).Return([]model.Resolution{
+					{IssueID: "BE-1", Project: "BE", Created: now.Add(-3 * time.Hour), Resolved: now.Add(-time.Hour)},
+					// out of window
+					{IssueID: "BE-2", Project: "BE", Created: now.Add(-48 * time.Hour), Resolved: now.Add(-25 * time.Hour)},
+					// unresolved
+					{IssueID: "BE-3", Project: "BE", Created: now.Add(-48 * time.Hour)},
+				}, nil)
+				// resolutions observed before restart are not observed again
+			},
+			expectedLastResolutions: map[string]map[string]time.Time{
+				"bugs": {"BE-1": now.Add(-time.Hour)},
+			},
+		},
+		{
+			tcase:           "new resolution",
+			lastResolutions: map[string]map[string]time.Time{"bugs": {}},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetResolutions(expectedQuery).Return([]model.Resolution{
+					{IssueID: "BE-1", Project: "BE", Created: now.Add(-3 * time.Hour), Resolved: now.Add(-time.Hour)},
+				}, nil)
+				m.EXPECT().ObserveResolution("bugs", "BE", float64(7200))
+			},
+			expectedLastResolutions: map[string]map[string]time.Time{
+				"bugs": {"BE-1": now.Add(-time.Hour)},
+			},
+		},
+		{
+			tcase: "observed, reopened and forgotten resolutions",
+			lastResolutions: map[string]map[string]time.Time{
+				"bugs": {
+					"BE-1": now.Add(-time.Hour),
+					"BE-4": now.Add(-10 * time.Hour),
+					"BE-5": now.Add(-25 * time.Hour),
+				},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetResolutions(expectedQuery).Return([]model.Resolution{
+					{IssueID: "BE-1", Project: "BE", Created: now.Add(-3 * time.Hour), Resolved: now.Add(-time.Hour)},
+					{IssueID: "BE-4", Project: "BE", Created: now.Add(-24 * time.Hour), Resolved: now.Add(-2 * time.Hour)},
+					{IssueID: "FE-1", Project: "FE", Created: now.Add(-time.Hour), Resolved: now},
+				}, nil)
+				m.EXPECT().ObserveResolution("bugs", "BE", float64(22*3600))
+				m.EXPECT().ObserveResolution("bugs", "FE", float64(3600))
+			},
+			expectedLastResolutions: map[string]map[string]time.Time{
+				"bugs": {
+					"BE-1": now.Add(-time.Hour),
+					"BE-4": now.Add(-2 * time.Hour),
+					"FE-1": now,
+				},
+			},
+		},
+		{
+			tcase:           "get resolutions error",
+			lastResolutions: map[string]map[string]time.Time{},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetResolutions(expectedQuery).Return(nil, errors.New("get resolutions error"))
+				m.EXPECT().ErrorInc("bugs", errors.New("get resolutions error"))
+			},
+			expectedLastResolutions: map[string]map[string]time.Time{},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		youTracker := NewMockyouTracker(ctrl)
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes:      make(map[string]string),
+			youTracker:      youTracker,
+			sources:         map[string]Source{config.SourceYouTrack: youTracker},
+			metricser:       metricser,
+			lastResolutions: testUnit.lastResolutions,
+			queries:         queries,
+			now:             func() time.Time { return now },
+		}
+
+		testUnit.expectFunc(youTracker, metricser)
+		monitoring.RefreshMetrics()
+
+		assert.Equal(t, testUnit.expectedLastResolutions, monitoring.lastResolutions, testUnit.tcase)
+	}
+}
+
+func TestMonitoring_resetMetrics(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	type testTableData struct {
+		tcase      string
+		queryName  string
+		query      config.Query
+		expectFunc func(m *Mockmetricser)
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:     "issues",
+			queryName: "issues",
+			query:     config.Query{Type: config.TypeIssues, Query: "#Unresolved", Title: config.TitleLabel},
+			expectFunc: func(m *Mockmetricser) {
+				m.EXPECT().DisableMonitoring("issues", "", model.Issue{ID: "YT-100", Title: "Test issue"})
+			},
+		},
+		{
+			tcase:     "work items",
+			queryName: "time",
+			query:     config.Query{Type: config.TypeWorkItems, Query: "#Resolved"},
+			expectFunc: func(m *Mockmetricser) {
+				m.EXPECT().SetSpentMinutes("time", model.SpentTime{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}, 0)
+				m.EXPECT().SetEstimationMinutes("time", "YT", "YT-100", 0)
+			},
+		},
+		{
+			tcase:     "count",
+			queryName: "count",
+			query:     config.Query{Type: config.TypeCount, Query: "#Unresolved", Project: "YT"},
+			expectFunc: func(m *Mockmetricser) {
+				m.EXPECT().SetIssuesCount("count", "YT", 0)
+			},
+		},
+		{
+			tcase:     "issues with sla",
+			queryName: "sla",
+			query:     config.Query{Type: config.TypeIssues, Query: "#Unresolved", Title: config.TitleNone, SLA: &config.SLA{ThresholdSeconds: 3600}},
+			expectFunc: func(m *Mockmetricser) {
+				m.EXPECT().SetSLABreached("sla", "YT", "YT-100", false)
+				m.EXPECT().SetSLA("sla", 0, math.Inf(1))
+			},
+		},
+		{
+			tcase:     "count with thresholds",
+			queryName: "count thresholds",
+			query:     config.Query{Type: config.TypeCount, Query: "#Unresolved", Thresholds: &config.Thresholds{Warning: &config.Threshold{Count: 1}}},
+			expectFunc: func(m *Mockmetricser) {
+				m.EXPECT().SetIssuesCount("count thresholds", "", 0)
+				m.EXPECT().SetQueryStatus("count thresholds", "")
+			},
+		},
+		{
+			tcase:      "resolution",
+			queryName:  "resolution",
+			query:      config.Query{Type: config.TypeResolution, Query: "Type: Bug"},
+			expectFunc: func(m *Mockmetricser) {},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes: make(map[string]string),
+			metricser:  metricser,
+			lastActiveIssues: map[string]map[string]model.Issue{
+				"issues": {"YT-100 Test issue": {ID: "YT-100", Title: "Test issue"}},
+			},
+			lastTimeTracking: map[string]timeTracking{
+				"time": {
+					spent: map[model.SpentTime]int{
+						{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}: 60,
+					},
+					estimation: map[string]model.TimeTracking{
+						"YT-100": {IssueID: "YT-100", Project: "YT", EstimationMinutes: 120},
+					},
+					workItems: map[string]workItem{"1-1": {minutes: 60}},
+				},
+			},
+			lastSLAIssues: map[string]map[string]model.SLAIssue{
+				"sla": {"YT-100 Test issue": {Issue: model.Issue{ID: "YT-100", Title: "Test issue"}, Project: "YT"}},
+			},
+			lastResolutions: map[string]map[string]time.Time{
+				"resolution": {"YT-100": time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC)},
+			},
+			status: map[string]QueryStatus{"count thresholds": {Severity: model.SeverityWarning, Issues: 1}},
+		}
+
+		testUnit.expectFunc(metricser)
+		monitoring.resetMetrics(testUnit.queryName, testUnit.query)
+
+		_, issuesOk := monitoring.lastActiveIssues[testUnit.queryName]
+		_, timeTrackingOk := monitoring.lastTimeTracking[testUnit.queryName]
+		_, slaIssuesOk := monitoring.lastSLAIssues[testUnit.queryName]
+		_, resolutionsOk := monitoring.lastResolutions[testUnit.queryName]
+		_, statusOk := monitoring.status[testUnit.queryName]
+		assert.False(t, issuesOk || timeTrackingOk || slaIssuesOk || resolutionsOk || statusOk, testUnit.tcase)
+	}
+}
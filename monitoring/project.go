@@ -0,0 +1,120 @@
+package monitoring
+
+import (
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"sort"
+	"strings"
+)
+
+// refreshProjects refreshes discovered projects and their queries, returns discovered projects count.
+func (m *Monitoring) refreshProjects() (int, error) {
+	projects, err := m.youTracker.GetProjects()
+	if err != nil {
+		return 0, err
+	}
+
+	current := make(map[string]model.Project)
+	for _, project := range projects {
+		if m.discovered(project) {
+			current[project.ShortName] = project
+		}
+	}
+
+	// Queries are generated before metrics are touched, so failed discovery keeps the previous state
+	queries, err := m.discoveryQueries(current)
+	if err != nil {
+		return 0, err
+	}
+
+	// Disable irrelevant and changed projects
+	for key, project := range m.lastProjects {
+		if currentProject, ok := current[key]; !ok || currentProject != project {
+			m.metricser.DisableProject(project)
+		}
+	}
+
+	// Enable new and changed projects
+	for key, project := range current {
+		if lastProject, ok := m.lastProjects[key]; !ok || lastProject != project {
+			m.metricser.EnableProject(project)
+		}
+	}
+
+	for queryName, query := range m.discoveredQueries {
+		if _, ok := queries[queryName]; !ok {
+			m.resetMetrics(queryName, query)
+		}
+	}
+
+	m.lastProjects = current
+	m.discoveredQueries = queries
+	return len(current), nil
+}
+
+// discoveryQueries generates count query and template queries of each project.
+// Generated query names must be unique and must not collide with configured ones.
+func (m *Monitoring) discoveryQueries(projects map[string]model.Project) (map[string]config.Query, error) {
+	queries := make(map[string]config.Query, len(projects))
+	add := func(source, queryName string, query config.Query) error {
+		_, configured := m.queries[queryName]
+		_, generated := queries[queryName]
+		if configured || generated {
+			return fmt.Errorf("%v: duplicate query %v", source, queryName)
+		}
+		queries[queryName] = query
+		return nil
+	}
+
+	for _, key := range sortedProjects(projects) {
+		err := add("project discovery", config.ProjectQueryPrefix+key, config.Query{
+			Type:    config.TypeCount,
+			Query:   strings.TrimSpace(fmt.Sprintf("project: %v %v", key, m.discovery.Query)),
+			Project: key,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedTemplates(m.templates) {
+		for _, key := range sortedProjects(projects) {
+			queryName, query, err := m.templates[name].Expand(name, key)
+			if err != nil {
+				return nil, fmt.Errorf("query template %v: %v", name, err)
+			}
+			err = add("query template "+name, queryName, query)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return queries, nil
+}
+
+func sortedProjects(projects map[string]model.Project) []string {
+	keys := make([]string, 0, len(projects))
+	for key := range projects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedTemplates(templates map[string]config.QueryTemplate) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *Monitoring) discovered(project model.Project) bool {
+	if project.Archived && !m.discovery.IncludeArchived {
+		return false
+	}
+	return m.discovery.FilterRegexp.MatchString(project.ShortName)
+}
@@ -0,0 +1,197 @@
+package monitoring
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"text/template"
+)
+
+func TestMonitoring_RefreshMetrics_ProjectDiscovery(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	discovery := &config.ProjectDiscovery{
+		FilterRegexp: regexp.MustCompile("^(BE|FE|OLD|QA)$"),
+		Query:        "#Unresolved",
+	}
+
+	type testTableData struct {
+		tcase                     string
+		queries                   map[string]config.Query
+		templates                 map[string]config.QueryTemplate
+		lastProjects              map[string]model.Project
+		discoveredQueries         map[string]config.Query
+		expectFunc                func(yt *MockyouTracker, m *Mockmetricser)
+		expectedLastProjects      map[string]model.Project
+		expectedDiscoveredQueries map[string]config.Query
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "1 new, 1 changed, 1 unchanged, 1 removed, 1 archived, 1 filtered",
+			queries: map[string]config.Query{
+				"critical": {Type: config.TypeIssues, Query: "Priority: Critical", Title: config.TitleLabel},
+			},
+			lastProjects: map[string]model.Project{
+				"BE": {ShortName: "BE", Name: "Backend", Leader: "john"},
+				"QA": {ShortName: "QA", Name: "Quality", Leader: "bob"},
+				"RM": {ShortName: "RM", Name: "Removed", Leader: "bob"},
+			},
+			discoveredQueries: map[string]config.Query{
+				"project_BE": {Type: config.TypeCount, Query: "project: BE #Unresolved", Project: "BE"},
+				"project_RM": {Type: config.TypeCount, Query: "project: RM #Unresolved", Project: "RM"},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetProjects().Return([]model.Project{
+					{ShortName: "BE", Name: "Backend", Leader: "jane"},
+					{ShortName: "FE", Name: "Frontend", Leader: "john"},
+					{ShortName: "OLD", Name: "Legacy", Leader: "john", Archived: true},
+					{ShortName: "QA", Name: "Quality", Leader: "bob"},
+					{ShortName: "MOB", Name: "Mobile", Leader: "john"},
+				}, nil)
+
+				m.EXPECT().DisableProject(model.Project{ShortName: "BE", Name: "Backend", Leader: "john"})
+				m.EXPECT().DisableProject(model.Project{ShortName: "RM", Name: "Removed", Leader: "bob"})
+				m.EXPECT().EnableProject(model.Project{ShortName: "BE", Name: "Backend", Leader: "jane"})
+				m.EXPECT().EnableProject(model.Project{ShortName: "FE", Name: "Frontend", Leader: "john"})
+				m.EXPECT().SetIssuesCount("project_RM", "RM", 0)
+
+				// configured query
+				yt.EXPECT().GetIssuesIfChanged("Priority: Critical", gomock.Any()).Return(map[string]model.Issue{}, "hash", true, nil)
+
+				// generated queries
+				yt.EXPECT().GetIssuesCount("project: BE #Unresolved").Return(1, nil)
+				m.EXPECT().SetIssuesCount("project_BE", "BE", 1)
+				yt.EXPECT().GetIssuesCount("project: FE #Unresolved").Return(0, nil)
+				m.EXPECT().SetIssuesCount("project_FE", "FE", 0)
+				yt.EXPECT().GetIssuesCount("project: QA #Unresolved").Return(0, nil)
+				m.EXPECT().SetIssuesCount("project_QA", "QA", 0)
+			},
+			expectedLastProjects: map[string]model.Project{
+				"BE": {ShortName: "BE", Name: "Backend", Leader: "jane"},
+				"FE": {ShortName: "FE", Name: "Frontend", Leader: "john"},
+				"QA": {ShortName: "QA", Name: "Quality", Leader: "bob"},
+			},
+			expectedDiscoveredQueries: map[string]config.Query{
+				"project_BE": {Type: config.TypeCount, Query: "project: BE #Unresolved", Project: "BE"},
+				"project_FE": {Type: config.TypeCount, Query: "project: FE #Unresolved", Project: "FE"},
+				"project_QA": {Type: config.TypeCount, Query: "project: QA #Unresolved", Project: "QA"},
+			},
+		},
+		{
+			tcase:   "query templates",
+			queries: map[string]config.Query{},
+			templates: map[string]config.QueryTemplate{
+				"critical": {
+					Query:    config.Query{Type: config.TypeIssues, Query: "project: {{.Project}} Priority: Critical", Title: config.TitleLabel},
+					Template: template.Must(template.New("critical").Parse("project: {{.Project}} Priority: Critical")),
+				},
+			},
+			lastProjects: map[string]model.Project{"RM": {ShortName: "RM", Name: "Removed", Leader: "bob"}},
+			discoveredQueries: map[string]config.Query{
+				"project_RM":  {Type: config.TypeCount, Query: "project: RM #Unresolved", Project: "RM"},
+				"critical_RM": {Type: config.TypeIssues, Query: "project: RM Priority: Critical", Project: "RM", Title: config.TitleLabel},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetProjects().Return([]model.Project{{ShortName: "BE", Name: "Backend", Leader: "jane"}}, nil)
+
+				m.EXPECT().DisableProject(model.Project{ShortName: "RM", Name: "Removed", Leader: "bob"})
+				m.EXPECT().EnableProject(model.Project{ShortName: "BE", Name: "Backend", Leader: "jane"})
+				m.EXPECT().SetIssuesCount("project_RM", "RM", 0)
+
+				yt.EXPECT().GetIssuesCount("project: BE #Unresolved").Return(1, nil)
+				m.EXPECT().SetIssuesCount("project_BE", "BE", 1)
+				yt.EXPECT().GetIssuesIfChanged("project: BE Priority: Critical", gomock.Any()).Return(map[string]model.Issue{"BE-1 First": {ID: "BE-1", Title: "First"}}, "hash", true, nil)
+				m.EXPECT().EnableMonitoring("critical_BE", "BE", model.Issue{ID: "BE-1", Title: "First"})
+			},
+			expectedLastProjects: map[string]model.Project{"BE": {ShortName: "BE", Name: "Backend", Leader: "jane"}},
+			expectedDiscoveredQueries: map[string]config.Query{
+				"project_BE":  {Type: config.TypeCount, Query: "project: BE #Unresolved", Project: "BE"},
+				"critical_BE": {Type: config.TypeIssues, Query: "project: BE Priority: Critical", Project: "BE", Title: config.TitleLabel},
+			},
+		},
+		{
+			tcase: "duplicate query",
+			queries: map[string]config.Query{
+				"critical_BE": {Type: config.TypeIssues, Query: "project: BE Priority: Critical", Title: config.TitleLabel},
+			},
+			templates: map[string]config.QueryTemplate{
+				"critical": {
+					Query:    config.Query{Type: config.TypeIssues, Query: "project: {{.Project}} Priority: Critical", Title: config.TitleLabel},
+					Template: template.Must(template.New("critical").Parse("project: {{.Project}} Priority: Critical")),
+				},
+			},
+			lastProjects: map[string]model.Project{"BE": {ShortName: "BE", Name: "Backend", Leader: "john"}},
+			discoveredQueries: map[string]config.Query{
+				"project_BE": {Type: config.TypeCount, Query: "project: BE #Unresolved", Project: "BE"},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetProjects().Return([]model.Project{
+					{ShortName: "BE", Name: "Backend", Leader: "jane"},
+					{ShortName: "FE", Name: "Frontend", Leader: "john"},
+				}, nil)
+				m.EXPECT().ErrorInc("project_discovery", errors.New("query template critical: duplicate query critical_BE"))
+
+				// projects are not changed, configured and last discovered queries are refreshed
+				yt.EXPECT().GetIssuesIfChanged("project: BE Priority: Critical", gomock.Any()).Return(map[string]model.Issue{}, "hash", true, nil)
+				yt.EXPECT().GetIssuesCount("project: BE #Unresolved").Return(0, nil)
+				m.EXPECT().SetIssuesCount("project_BE", "BE", 0)
+			},
+			expectedLastProjects: map[string]model.Project{"BE": {ShortName: "BE", Name: "Backend", Leader: "john"}},
+			expectedDiscoveredQueries: map[string]config.Query{
+				"project_BE": {Type: config.TypeCount, Query: "project: BE #Unresolved", Project: "BE"},
+			},
+		},
+		{
+			tcase:        "get projects error",
+			queries:      map[string]config.Query{},
+			lastProjects: map[string]model.Project{"BE": {ShortName: "BE", Name: "Backend", Leader: "john"}},
+			discoveredQueries: map[string]config.Query{
+				"project_BE": {Type: config.TypeCount, Query: "project: BE #Unresolved", Project: "BE"},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetProjects().Return(nil, errors.New("get projects error"))
+				m.EXPECT().ErrorInc("project_discovery", errors.New("get projects error"))
+
+				// last discovered queries are refreshed
+				yt.EXPECT().GetIssuesCount("project: BE #Unresolved").Return(0, nil)
+				m.EXPECT().SetIssuesCount("project_BE", "BE", 0)
+			},
+			expectedLastProjects: map[string]model.Project{"BE": {ShortName: "BE", Name: "Backend", Leader: "john"}},
+			expectedDiscoveredQueries: map[string]config.Query{
+				"project_BE": {Type: config.TypeCount, Query: "project: BE #Unresolved", Project: "BE"},
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		youTracker := NewMockyouTracker(ctrl)
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes:        make(map[string]string),
+			youTracker:        youTracker,
+			sources:           map[string]Source{config.SourceYouTrack: youTracker},
+			metricser:         metricser,
+			lastActiveIssues:  map[string]map[string]model.Issue{},
+			lastProjects:      testUnit.lastProjects,
+			queries:           testUnit.queries,
+			discoveredQueries: testUnit.discoveredQueries,
+			templates:         testUnit.templates,
+			discovery:         discovery,
+		}
+
+		testUnit.expectFunc(youTracker, metricser)
+		monitoring.RefreshMetrics()
+
+		assert.Equal(t, testUnit.expectedLastProjects, monitoring.lastProjects, testUnit.tcase)
+		assert.Equal(t, testUnit.expectedDiscoveredQueries, monitoring.discoveredQueries, testUnit.tcase)
+	}
+}
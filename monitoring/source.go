@@ -0,0 +1,64 @@
+package monitoring
+
+import (
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"sort"
+)
+
+// source returns registered source of query which supports query type and settings.
+// Queries without source, like generated ones, use YouTrack source.
+func (m *Monitoring) source(query config.Query) (Source, error) {
+	name := query.Source
+	if name == "" {
+		name = config.SourceYouTrack
+	}
+
+	source, ok := m.sources[name]
+	if !ok {
+		return nil, fmt.Errorf("source is not registered: %v", name)
+	}
+
+	switch query.Type {
+	case config.TypeWorkItems:
+		_, ok = source.(getTimeTrackinger)
+	case config.TypeResolution:
+		_, ok = source.(getResolutionser)
+	}
+	if !ok {
+		return nil, fmt.Errorf("source %v: not supported for type: %v", name, query.Type)
+	}
+
+	if query.SLA != nil || query.Thresholds.HasAge() {
+		if _, ok = source.(getSLAIssueser); !ok {
+			return nil, fmt.Errorf("source %v: sla and age thresholds are not supported", name)
+		}
+	}
+
+	return source, nil
+}
+
+// CheckSources checks that sources of configured queries and templates are registered and support query settings.
+// All found problems are returned at once.
+func (m *Monitoring) CheckSources() []error {
+	var problems []error
+
+	names := make([]string, 0, len(m.queries))
+	for name := range m.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := m.source(m.queries[name]); err != nil {
+			problems = append(problems, fmt.Errorf("query %v: %v", name, err))
+		}
+	}
+
+	for _, name := range sortedTemplates(m.templates) {
+		if _, err := m.source(m.templates[name].Query); err != nil {
+			problems = append(problems, fmt.Errorf("query template %v: %v", name, err))
+		}
+	}
+
+	return problems
+}
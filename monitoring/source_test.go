@@ -0,0 +1,49 @@
+package monitoring
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMonitoring_CheckSources(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c := &config.Config{
+		Queries: map[string]config.Query{
+			"default":    {Type: config.TypeWorkItems, Query: "#Unresolved"},
+			"youtrack":   {Source: config.SourceYouTrack, Type: config.TypeIssues, Query: "#Unresolved", SLA: &config.SLA{ThresholdSeconds: 60}},
+			"file":       {Source: config.SourceFile, Type: config.TypeCount, Query: "issues.json"},
+			"file_items": {Source: config.SourceFile, Type: config.TypeWorkItems, Query: "issues.json"},
+			"file_sla":   {Source: config.SourceFile, Type: config.TypeIssues, Query: "issues.json", SLA: &config.SLA{ThresholdSeconds: 60}},
+			"file_age": {
+				Source:     config.SourceFile,
+				Type:       config.TypeIssues,
+				Query:      "issues.json",
+				Thresholds: &config.Thresholds{Warning: &config.Threshold{AgeSeconds: 60}},
+			},
+			"jira": {Source: "jira", Type: config.TypeIssues, Query: "project = BE"},
+		},
+		QueryTemplates: map[string]config.QueryTemplate{
+			"resolution": {Query: config.Query{Source: config.SourceFile, Type: config.TypeResolution, Query: "{{.Project}}.json"}},
+		},
+	}
+
+	youTracker := NewMockyouTracker(ctrl)
+	monitoring := New(youTracker, NewMockmetricser(ctrl), c, nil)
+	monitoring.RegisterSource(config.SourceYouTrack, youTracker)
+	monitoring.RegisterSource(config.SourceFile, NewMockSource(ctrl))
+
+	assert.Equal(t, []error{
+		errors.New("query file_age: source file: sla and age thresholds are not supported"),
+		errors.New("query file_items: source file: not supported for type: work_items"),
+		errors.New("query file_sla: source file: sla and age thresholds are not supported"),
+		errors.New("query jira: source is not registered: jira"),
+		errors.New("query template resolution: source file: not supported for type: resolution"),
+	}, monitoring.CheckSources())
+}
@@ -0,0 +1,94 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"net/http"
+	"time"
+)
+
+// QueryStatus represents query status evaluated by query thresholds.
+type QueryStatus struct {
+	Severity              string  `json:"severity"`
+	Issues                int     `json:"issues"`
+	OldestIssueAgeSeconds float64 `json:"oldest_issue_age_seconds"`
+}
+
+// Status returns statuses of queries with thresholds.
+func (m *Monitoring) Status() map[string]QueryStatus {
+	m.statusLock.RLock()
+	defer m.statusLock.RUnlock()
+
+	status := make(map[string]QueryStatus, len(m.status))
+	for queryName, queryStatus := range m.status {
+		status[queryName] = queryStatus
+	}
+	return status
+}
+
+// ServeStatus writes statuses of queries with thresholds as JSON.
+func (m *Monitoring) ServeStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Queries map[string]QueryStatus `json:"queries"`
+	}{
+		Queries: m.Status(),
+	})
+}
+
+// refreshStatus evaluates query thresholds for found issues.
+// Issues created time is used for age thresholds and is zero if not requested.
+func (m *Monitoring) refreshStatus(queryName string, query config.Query, issues map[string]model.SLAIssue, count int) {
+	var oldest time.Duration
+	now := m.now()
+	for _, issue := range issues {
+		if issue.Created.IsZero() {
+			continue
+		}
+		if age := now.Sub(issue.Created); age > oldest {
+			oldest = age
+		}
+	}
+
+	status := QueryStatus{
+		Severity:              severity(query.Thresholds, count, oldest),
+		Issues:                count,
+		OldestIssueAgeSeconds: oldest.Seconds(),
+	}
+
+	m.statusLock.Lock()
+	m.status[queryName] = status
+	m.statusLock.Unlock()
+
+	m.metricser.SetQueryStatus(queryName, status.Severity)
+}
+
+// resetStatus removes status of query which is not refreshed anymore.
+func (m *Monitoring) resetStatus(queryName string) {
+	m.statusLock.Lock()
+	delete(m.status, queryName)
+	m.statusLock.Unlock()
+
+	m.metricser.SetQueryStatus(queryName, "")
+}
+
+// severity returns severity of the highest reached threshold.
+func severity(thresholds *config.Thresholds, count int, oldest time.Duration) string {
+	switch {
+	case reached(thresholds.Critical, count, oldest):
+		return model.SeverityCritical
+	case reached(thresholds.Warning, count, oldest):
+		return model.SeverityWarning
+	default:
+		return model.SeverityOK
+	}
+}
+
+func reached(threshold *config.Threshold, count int, oldest time.Duration) bool {
+	if threshold == nil {
+		return false
+	}
+	return (threshold.Count > 0 && count >= threshold.Count) ||
+		(threshold.AgeSeconds > 0 && oldest >= time.Duration(threshold.AgeSeconds)*time.Second)
+}
@@ -0,0 +1,163 @@
+package monitoring
+
+import (
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/config"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMonitoring_RefreshMetrics_Status(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2019, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	type testTableData struct {
+		tcase          string
+		queries        map[string]config.Query
+		expectFunc     func(yt *MockyouTracker, m *Mockmetricser)
+		expectedStatus map[string]QueryStatus
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "age threshold",
+			queries: map[string]config.Query{
+				"critical": {
+					Type:       config.TypeIssues,
+					Query:      "Priority: Critical",
+					Title:      config.TitleNone,
+					Thresholds: &config.Thresholds{Warning: &config.Threshold{AgeSeconds: 3600}, Critical: &config.Threshold{Count: 3}},
+				},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetSLAIssues("Priority: Critical", "").Return(map[string]model.SLAIssue{
+					"BE-1 First":  {Issue: model.Issue{ID: "BE-1", Title: "First"}, Project: "BE", Created: now.Add(-2 * time.Hour)},
+					"BE-2 Second": {Issue: model.Issue{ID: "BE-2", Title: "Second"}, Project: "BE", Created: now.Add(-time.Hour / 2)},
+				}, nil)
+				m.EXPECT().EnableMonitoring("critical", "", model.Issue{ID: "BE-1"})
+				m.EXPECT().EnableMonitoring("critical", "", model.Issue{ID: "BE-2"})
+				m.EXPECT().SetQueryStatus("critical", model.SeverityWarning)
+			},
+			expectedStatus: map[string]QueryStatus{
+				"critical": {Severity: model.SeverityWarning, Issues: 2, OldestIssueAgeSeconds: 7200},
+			},
+		},
+		{
+			tcase: "count threshold",
+			queries: map[string]config.Query{
+				"unresolved": {
+					Type:       config.TypeCount,
+					Query:      "#Unresolved",
+					Thresholds: &config.Thresholds{Warning: &config.Threshold{Count: 1}, Critical: &config.Threshold{Count: 2}},
+				},
+				"unassigned": {
+					Type:       config.TypeIssues,
+					Query:      "#Unassigned",
+					Title:      config.TitleNone,
+					Thresholds: &config.Thresholds{Warning: &config.Threshold{Count: 1}},
+				},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetIssuesCount("#Unresolved").Return(2, nil)
+				m.EXPECT().SetIssuesCount("unresolved", "", 2)
+				m.EXPECT().SetQueryStatus("unresolved", model.SeverityCritical)
+				yt.EXPECT().GetIssuesIfChanged("#Unassigned", gomock.Any()).Return(map[string]model.Issue{}, "hash", true, nil)
+				m.EXPECT().SetQueryStatus("unassigned", model.SeverityOK)
+			},
+			expectedStatus: map[string]QueryStatus{
+				"unresolved": {Severity: model.SeverityCritical, Issues: 2},
+				"unassigned": {Severity: model.SeverityOK},
+			},
+		},
+		{
+			tcase: "get issues error",
+			queries: map[string]config.Query{
+				"unresolved": {
+					Type:       config.TypeCount,
+					Query:      "#Unresolved",
+					Thresholds: &config.Thresholds{Warning: &config.Threshold{Count: 1}},
+				},
+			},
+			expectFunc: func(yt *MockyouTracker, m *Mockmetricser) {
+				yt.EXPECT().GetIssuesCount("#Unresolved").Return(0, errors.New("get issues count error"))
+				m.EXPECT().ErrorInc("unresolved", errors.New("get issues count error"))
+			},
+			expectedStatus: map[string]QueryStatus{},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		youTracker := NewMockyouTracker(ctrl)
+		metricser := NewMockmetricser(ctrl)
+
+		monitoring := &Monitoring{
+			lastHashes:       make(map[string]string),
+			youTracker:       youTracker,
+			sources:          map[string]Source{config.SourceYouTrack: youTracker},
+			metricser:        metricser,
+			lastActiveIssues: map[string]map[string]model.Issue{},
+			queries:          testUnit.queries,
+			now:              func() time.Time { return now },
+			status:           map[string]QueryStatus{},
+		}
+
+		testUnit.expectFunc(youTracker, metricser)
+		monitoring.RefreshMetrics()
+
+		assert.Equal(t, testUnit.expectedStatus, monitoring.Status(), testUnit.tcase)
+	}
+}
+
+func TestMonitoring_ServeStatus(t *testing.T) {
+	t.Parallel()
+
+	monitoring := &Monitoring{
+		lastHashes: make(map[string]string),
+		status: map[string]QueryStatus{
+			"critical": {Severity: model.SeverityWarning, Issues: 2, OldestIssueAgeSeconds: 7200},
+		},
+	}
+
+	w := httptest.NewRecorder()
+	monitoring.ServeStatus(w, httptest.NewRequest("GET", "/status", nil))
+
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"queries": {"critical": {"severity": "warning", "issues": 2, "oldest_issue_age_seconds": 7200}}}`, w.Body.String())
+}
+
+func Test_severity(t *testing.T) {
+	t.Parallel()
+
+	thresholds := &config.Thresholds{
+		Warning:  &config.Threshold{Count: 5, AgeSeconds: 3600},
+		Critical: &config.Threshold{Count: 10},
+	}
+
+	type testTableData struct {
+		tcase      string
+		thresholds *config.Thresholds
+		count      int
+		oldest     time.Duration
+		expected   string
+	}
+
+	testTable := []testTableData{
+		{tcase: "ok", thresholds: thresholds, count: 4, oldest: time.Minute, expected: model.SeverityOK},
+		{tcase: "warning by count", thresholds: thresholds, count: 5, oldest: time.Minute, expected: model.SeverityWarning},
+		{tcase: "warning by age", thresholds: thresholds, count: 1, oldest: time.Hour, expected: model.SeverityWarning},
+		{tcase: "critical", thresholds: thresholds, count: 10, oldest: time.Hour, expected: model.SeverityCritical},
+		{tcase: "only critical", thresholds: &config.Thresholds{Critical: &config.Threshold{Count: 1}}, count: 0, expected: model.SeverityOK},
+	}
+
+	for _, testUnit := range testTable {
+		assert.Equal(t, testUnit.expected, severity(testUnit.thresholds, testUnit.count, testUnit.oldest), testUnit.tcase)
+	}
+}
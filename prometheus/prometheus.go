@@ -3,23 +3,290 @@ package prometheus
 import (
 	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
 	pr "github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
 )
 
 // Metrics describes Prometheus metric collector.
 type Metrics struct {
-	issues gaugeIniter
-	errors counterIniter
+	issues     gaugeIniter
+	info       gaugeIniter
+	sla        slaGauges
+	spent      gaugeIniter
+	spentTotal counterIniter
+	estimation gaugeIniter
+	sprint     sprintMetrics
+	project    gaugeIniter
+	count      gaugeIniter
+	status     gaugeIniter
+	resolution observerIniter
+	token      tokenMetrics
+	requests   requestMetrics
+	circuit    pr.Gauge
+	cache      counterIniter
+	errors     counterIniter
 }
 
-// New creates Metrics.
-func New() *Metrics {
+type requestMetrics struct {
+	queued        pr.Gauge
+	wait          pr.Observer
+	requestBytes  counterIniter
+	responseBytes counterIniter
+	duration      observerIniter
+}
+
+type tokenMetrics struct {
+	expiry        pr.Gauge
+	refreshErrors counterIniter
+}
+
+type slaGauges struct {
+	breached      gaugeIniter
+	breachedCount gaugeIniter
+	timeToBreach  gaugeIniter
+}
+
+type sprintMetrics struct {
+	issues    gaugeIniter
+	remaining gaugeIniter
+	start     gaugeIniter
+	finish    gaugeIniter
+	errors    counterIniter
+}
+
+// New creates Metrics registered by registerer. Resolution histogram of all queries has passed buckets.
+func New(registerer pr.Registerer, resolutionBuckets []float64) *Metrics {
 	issues := pr.NewGaugeVec(
 		pr.GaugeOpts{
 			Subsystem: "youtrack",
 			Name:      "issues",
 			Help:      "Query issues",
 		},
-		[]string{"query", "id", "title"},
+		[]string{"query", "project", "id", "title", "url"},
+	)
+
+	info := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "issue_info",
+			Help:      "Query issues info",
+		},
+		[]string{"query", "id", "title", "url"},
+	)
+
+	slaBreached := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "issue_sla_breached",
+			Help:      "Query issues SLA breach",
+		},
+		[]string{"query", "project", "id"},
+	)
+
+	slaBreachedCount := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "query_sla_breached_issues",
+			Help:      "Query SLA breached issues count",
+		},
+		[]string{"query"},
+	)
+
+	slaTimeToBreach := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "query_sla_time_to_breach_seconds",
+			Help:      "Query seconds to the nearest SLA breach",
+		},
+		[]string{"query"},
+	)
+
+	spent := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "issue_spent_minutes",
+			Help:      "Query issues spent time minutes",
+		},
+		[]string{"query", "project", "id", "author", "type"},
+	)
+
+	spentTotal := pr.NewCounterVec(
+		pr.CounterOpts{
+			Subsystem: "youtrack",
+			Name:      "spent_minutes_total",
+			Help:      "Query issues spent time minutes counter",
+		},
+		[]string{"query", "project", "author", "type"},
+	)
+
+	estimation := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "issue_estimation_minutes",
+			Help:      "Query issues estimation minutes",
+		},
+		[]string{"query", "project", "id"},
+	)
+
+	sprintIssues := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "sprint_issues",
+			Help:      "Agile board current sprint issues in column",
+		},
+		[]string{"board", "sprint", "column"},
+	)
+
+	sprintRemaining := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "sprint_remaining_estimation_minutes",
+			Help:      "Agile board current sprint remaining estimation minutes",
+		},
+		[]string{"board", "sprint"},
+	)
+
+	sprintStart := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "sprint_start_timestamp_seconds",
+			Help:      "Agile board current sprint start timestamp",
+		},
+		[]string{"board", "sprint"},
+	)
+
+	sprintFinish := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "sprint_finish_timestamp_seconds",
+			Help:      "Agile board current sprint finish timestamp",
+		},
+		[]string{"board", "sprint"},
+	)
+
+	sprintErrors := pr.NewCounterVec(
+		pr.CounterOpts{
+			Subsystem: "youtrack",
+			Name:      "sprint_errors_total",
+			Help:      "Agile board current sprint errors counter",
+		},
+		[]string{"board", "error"},
+	)
+
+	project := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "project_info",
+			Help:      "Discovered projects info",
+		},
+		[]string{"project", "name", "leader", "archived"},
+	)
+
+	count := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "query_issues",
+			Help:      "Query issues count",
+		},
+		[]string{"query", "project"},
+	)
+
+	status := pr.NewGaugeVec(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "query_status",
+			Help:      "Query status by thresholds",
+		},
+		[]string{"query", "severity"},
+	)
+
+	resolution := pr.NewHistogramVec(
+		pr.HistogramOpts{
+			Subsystem: "youtrack",
+			Name:      "issue_resolution_seconds",
+			Help:      "Query issues resolution time seconds",
+			Buckets:   resolutionBuckets,
+		},
+		[]string{"query", "project"},
+	)
+
+	tokenExpiry := pr.NewGauge(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "token_expiry_timestamp_seconds",
+			Help:      "Hub access token expiry timestamp",
+		},
+	)
+
+	tokenRefreshErrors := pr.NewCounterVec(
+		pr.CounterOpts{
+			Subsystem: "youtrack",
+			Name:      "token_refresh_errors_total",
+			Help:      "Hub access token refresh errors counter",
+		},
+		[]string{"reason"},
+	)
+
+	requestsQueued := pr.NewGauge(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "queued_requests",
+			Help:      "Requests waiting for rate limiter",
+		},
+	)
+
+	requestWait := pr.NewHistogram(
+		pr.HistogramOpts{
+			Subsystem: "youtrack",
+			Name:      "request_wait_seconds",
+			Help:      "Time spent waiting for rate limiter",
+			Buckets:   pr.DefBuckets,
+		},
+	)
+
+	httpRequestBytes := pr.NewCounterVec(
+		pr.CounterOpts{
+			Subsystem: "youtrack",
+			Name:      "http_request_bytes_total",
+			Help:      "HTTP request body bytes sent",
+		},
+		[]string{"path", "code"},
+	)
+
+	httpResponseBytes := pr.NewCounterVec(
+		pr.CounterOpts{
+			Subsystem: "youtrack",
+			Name:      "http_response_bytes_total",
+			Help:      "HTTP response body bytes received before decompression",
+		},
+		[]string{"path", "code"},
+	)
+
+	httpDuration := pr.NewHistogramVec(
+		pr.HistogramOpts{
+			Subsystem: "youtrack",
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency seconds",
+			Buckets:   pr.DefBuckets,
+		},
+		[]string{"path", "code"},
+	)
+
+	circuit := pr.NewGauge(
+		pr.GaugeOpts{
+			Subsystem: "youtrack",
+			Name:      "circuit_state",
+			Help:      "Circuit breaker state: 0 - closed, 1 - open, 2 - half-open",
+		},
+	)
+
+	cache := pr.NewCounterVec(
+		pr.CounterOpts{
+			Subsystem: "youtrack",
+			Name:      "cache_requests_total",
+			Help:      "Cached requests counter",
+		},
+		[]string{"result"},
 	)
 
 	errors := pr.NewCounterVec(
@@ -31,23 +298,216 @@ func New() *Metrics {
 		[]string{"query", "error"},
 	)
 
-	pr.MustRegister(issues)
-	pr.MustRegister(errors)
+	registerer.MustRegister(
+		issues,
+		info,
+		slaBreached,
+		slaBreachedCount,
+		slaTimeToBreach,
+		spent,
+		spentTotal,
+		estimation,
+		sprintIssues,
+		sprintRemaining,
+		sprintStart,
+		sprintFinish,
+		sprintErrors,
+		project,
+		count,
+		status,
+		resolution,
+		tokenExpiry,
+		tokenRefreshErrors,
+		requestsQueued,
+		requestWait,
+		httpRequestBytes,
+		httpResponseBytes,
+		httpDuration,
+		circuit,
+		cache,
+		errors,
+	)
 
 	return &Metrics{
 		issues: issues,
-		errors: errors,
+		info:   info,
+		sla: slaGauges{
+			breached:      slaBreached,
+			breachedCount: slaBreachedCount,
+			timeToBreach:  slaTimeToBreach,
+		},
+		spent:      spent,
+		spentTotal: spentTotal,
+		estimation: estimation,
+		sprint: sprintMetrics{
+			issues:    sprintIssues,
+			remaining: sprintRemaining,
+			start:     sprintStart,
+			finish:    sprintFinish,
+			errors:    sprintErrors,
+		},
+		project:    project,
+		count:      count,
+		status:     status,
+		resolution: resolution,
+		token: tokenMetrics{
+			expiry:        tokenExpiry,
+			refreshErrors: tokenRefreshErrors,
+		},
+		requests: requestMetrics{
+			queued:        requestsQueued,
+			wait:          requestWait,
+			requestBytes:  httpRequestBytes,
+			responseBytes: httpResponseBytes,
+			duration:      httpDuration,
+		},
+		circuit: circuit,
+		cache:   cache,
+		errors:  errors,
 	}
 }
 
 // EnableMonitoring turns on metric for issue.
-func (p *Metrics) EnableMonitoring(queryName string, issue model.Issue) {
-	p.issues.WithLabelValues(queryName, issue.ID, issue.Title).Set(1)
+func (p *Metrics) EnableMonitoring(queryName, project string, issue model.Issue) {
+	p.issues.WithLabelValues(queryName, project, issue.ID, issue.Title, issue.URL).Set(1)
 }
 
 // DisableMonitoring turns off metric for issue.
-func (p *Metrics) DisableMonitoring(queryName string, issue model.Issue) {
-	p.issues.WithLabelValues(queryName, issue.ID, issue.Title).Set(0)
+func (p *Metrics) DisableMonitoring(queryName, project string, issue model.Issue) {
+	p.issues.WithLabelValues(queryName, project, issue.ID, issue.Title, issue.URL).Set(0)
+}
+
+// EnableInfo turns on info metric for issue.
+func (p *Metrics) EnableInfo(queryName string, issue model.Issue) {
+	p.info.WithLabelValues(queryName, issue.ID, issue.Title, issue.URL).Set(1)
+}
+
+// DisableInfo turns off info metric for issue.
+func (p *Metrics) DisableInfo(queryName string, issue model.Issue) {
+	p.info.WithLabelValues(queryName, issue.ID, issue.Title, issue.URL).Set(0)
+}
+
+// SetSLABreached sets metric of issue SLA breach.
+func (p *Metrics) SetSLABreached(queryName, project, issueID string, breached bool) {
+	var value float64
+	if breached {
+		value = 1
+	}
+	p.sla.breached.WithLabelValues(queryName, project, issueID).Set(value)
+}
+
+// SetSLA sets query SLA breached issues count and time to the nearest breach.
+func (p *Metrics) SetSLA(queryName string, breachedCount int, timeToBreachSeconds float64) {
+	p.sla.breachedCount.WithLabelValues(queryName).Set(float64(breachedCount))
+	p.sla.timeToBreach.WithLabelValues(queryName).Set(timeToBreachSeconds)
+}
+
+// SetSpentMinutes sets time spent on issue by author with work type.
+func (p *Metrics) SetSpentMinutes(queryName string, spent model.SpentTime, minutes int) {
+	p.spent.WithLabelValues(queryName, spent.Project, spent.IssueID, spent.Author, spent.Type).Set(float64(minutes))
+}
+
+// AddSpentMinutes increases spent time counter, issue ID is omitted to keep counter low-cardinality.
+func (p *Metrics) AddSpentMinutes(queryName string, spent model.SpentTime, minutes int) {
+	p.spentTotal.WithLabelValues(queryName, spent.Project, spent.Author, spent.Type).Add(float64(minutes))
+}
+
+// SetEstimationMinutes sets issue estimation.
+func (p *Metrics) SetEstimationMinutes(queryName, project, issueID string, minutes int) {
+	p.estimation.WithLabelValues(queryName, project, issueID).Set(float64(minutes))
+}
+
+// SetSprint sets agile board sprint values.
+func (p *Metrics) SetSprint(sprint model.Sprint) {
+	for column, count := range sprint.Columns {
+		p.sprint.issues.WithLabelValues(sprint.Board, sprint.Name, column).Set(float64(count))
+	}
+	p.sprint.remaining.WithLabelValues(sprint.Board, sprint.Name).Set(float64(sprint.RemainingEstimationMinutes))
+	p.sprint.start.WithLabelValues(sprint.Board, sprint.Name).Set(timestamp(sprint.Start))
+	p.sprint.finish.WithLabelValues(sprint.Board, sprint.Name).Set(timestamp(sprint.Finish))
+}
+
+// ResetSprint sets all agile board sprint values to 0.
+func (p *Metrics) ResetSprint(sprint model.Sprint) {
+	for column := range sprint.Columns {
+		p.sprint.issues.WithLabelValues(sprint.Board, sprint.Name, column).Set(0)
+	}
+	p.sprint.remaining.WithLabelValues(sprint.Board, sprint.Name).Set(0)
+	p.sprint.start.WithLabelValues(sprint.Board, sprint.Name).Set(0)
+	p.sprint.finish.WithLabelValues(sprint.Board, sprint.Name).Set(0)
+}
+
+// EnableProject turns on info metric for project.
+func (p *Metrics) EnableProject(project model.Project) {
+	p.project.WithLabelValues(project.ShortName, project.Name, project.Leader, strconv.FormatBool(project.Archived)).Set(1)
+}
+
+// DisableProject turns off info metric for project.
+func (p *Metrics) DisableProject(project model.Project) {
+	p.project.WithLabelValues(project.ShortName, project.Name, project.Leader, strconv.FormatBool(project.Archived)).Set(0)
+}
+
+// SetIssuesCount sets query issues count.
+func (p *Metrics) SetIssuesCount(queryName, project string, count int) {
+	p.count.WithLabelValues(queryName, project).Set(float64(count))
+}
+
+// SetQueryStatus turns on metric of query status severity and turns off others.
+// Empty severity turns off all metrics of query status.
+func (p *Metrics) SetQueryStatus(queryName, severity string) {
+	for _, s := range model.Severities {
+		var value float64
+		if s == severity {
+			value = 1
+		}
+		p.status.WithLabelValues(queryName, s).Set(value)
+	}
+}
+
+// ObserveResolution observes issue resolution time in resolution histogram.
+func (p *Metrics) ObserveResolution(queryName, project string, seconds float64) {
+	p.resolution.WithLabelValues(queryName, project).Observe(seconds)
+}
+
+// SetTokenExpiry sets Hub access token expiry timestamp.
+func (p *Metrics) SetTokenExpiry(expiry time.Time) {
+	p.token.expiry.Set(timestamp(expiry))
+}
+
+// TokenRefreshErrorInc increments metric for Hub access token refresh error with passed reason.
+func (p *Metrics) TokenRefreshErrorInc(reason string) {
+	p.token.refreshErrors.WithLabelValues(reason).Inc()
+}
+
+// AddQueuedRequests adds delta to count of requests waiting for rate limiter.
+func (p *Metrics) AddQueuedRequests(delta int) {
+	p.requests.queued.Add(float64(delta))
+}
+
+// ObserveRequestWait observes time spent waiting for rate limiter.
+func (p *Metrics) ObserveRequestWait(seconds float64) {
+	p.requests.wait.Observe(seconds)
+}
+
+// ObserveHTTPRequest observes transferred bytes and latency of HTTP request.
+func (p *Metrics) ObserveHTTPRequest(path, code string, requestBytes, responseBytes int64, seconds float64) {
+	p.requests.requestBytes.WithLabelValues(path, code).Add(float64(requestBytes))
+	p.requests.responseBytes.WithLabelValues(path, code).Add(float64(responseBytes))
+	p.requests.duration.WithLabelValues(path, code).Observe(seconds)
+}
+
+// SetCircuitState sets circuit breaker state.
+func (p *Metrics) SetCircuitState(state int) {
+	p.circuit.Set(float64(state))
+}
+
+// CacheRequestInc increments metric for cache hit or miss.
+func (p *Metrics) CacheRequestInc(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	p.cache.WithLabelValues(result).Inc()
 }
 
 // ErrorInc increments metric for error
@@ -55,7 +515,20 @@ func (p *Metrics) ErrorInc(queryName string, err error) {
 	p.errors.WithLabelValues(queryName, err.Error()).Inc()
 }
 
-//go:generate mockgen -destination=prometheus_metrics_mocks.go -package=prometheus github.com/prometheus/client_golang/prometheus Counter,Gauge
+// SprintErrorInc increments metric for agile board sprint error.
+func (p *Metrics) SprintErrorInc(board string, err error) {
+	p.sprint.errors.WithLabelValues(board, err.Error()).Inc()
+}
+
+// timestamp converts time to Unix timestamp, zero time is converted to 0.
+func timestamp(t time.Time) float64 {
+	if t.IsZero() {
+		return 0
+	}
+	return float64(t.Unix())
+}
+
+//go:generate mockgen -destination=prometheus_metrics_mocks.go -package=prometheus github.com/prometheus/client_golang/prometheus Counter,Gauge,Observer
 //go:generate mockgen -source=prometheus.go -destination=prometheus_mocks.go -package=prometheus doc github.com/golang/mock/gomock
 
 type counterIniter interface {
@@ -65,3 +538,7 @@ type counterIniter interface {
 type gaugeIniter interface {
 	WithLabelValues(lvs ...string) pr.Gauge
 }
+
+type observerIniter interface {
+	WithLabelValues(lvs ...string) pr.Observer
+}
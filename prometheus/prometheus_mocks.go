@@ -87,3 +87,42 @@ func (m *MockgaugeIniter) WithLabelValues(lvs ...string) prometheus.Gauge {
 func (mr *MockgaugeIniterMockRecorder) WithLabelValues(lvs ...interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLabelValues", reflect.TypeOf((*MockgaugeIniter)(nil).WithLabelValues), lvs...)
 }
+
+// MockobserverIniter is a mock of observerIniter interface
+type MockobserverIniter struct {
+	ctrl     *gomock.Controller
+	recorder *MockobserverIniterMockRecorder
+}
+
+// MockobserverIniterMockRecorder is the mock recorder for MockobserverIniter
+type MockobserverIniterMockRecorder struct {
+	mock *MockobserverIniter
+}
+
+// NewMockobserverIniter creates a new mock instance
+func NewMockobserverIniter(ctrl *gomock.Controller) *MockobserverIniter {
+	mock := &MockobserverIniter{ctrl: ctrl}
+	mock.recorder = &MockobserverIniterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockobserverIniter) EXPECT() *MockobserverIniterMockRecorder {
+	return m.recorder
+}
+
+// WithLabelValues mocks base method
+func (m *MockobserverIniter) WithLabelValues(lvs ...string) prometheus.Observer {
+	varargs := []interface{}{}
+	for _, a := range lvs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "WithLabelValues", varargs...)
+	ret0, _ := ret[0].(prometheus.Observer)
+	return ret0
+}
+
+// WithLabelValues indicates an expected call of WithLabelValues
+func (mr *MockobserverIniterMockRecorder) WithLabelValues(lvs ...interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithLabelValues", reflect.TypeOf((*MockobserverIniter)(nil).WithLabelValues), lvs...)
+}
@@ -4,7 +4,9 @@ import (
 	e "errors"
 	"github.com/golang/mock/gomock"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	pr "github.com/prometheus/client_golang/prometheus"
 	"testing"
+	"time"
 )
 
 func TestPrometheus_ConsistentLabelCardinality(t *testing.T) {
@@ -16,15 +18,39 @@ func TestPrometheus_ConsistentLabelCardinality(t *testing.T) {
 		}
 	}()
 
-	p := New()
+	p := New(pr.NewRegistry(), []float64{60, 3600})
 	issue := model.Issue{
 		ID:    "YT-100",
 		Title: "Test issue",
 	}
 	queryName := "unassigned"
 
-	p.EnableMonitoring(queryName, issue)
-	p.DisableMonitoring(queryName, issue)
+	p.EnableMonitoring(queryName, "YT", issue)
+	p.DisableMonitoring(queryName, "YT", issue)
+	p.EnableInfo(queryName, issue)
+	p.DisableInfo(queryName, issue)
+	p.SetSLABreached(queryName, "YT", "YT-100", true)
+	p.SetSLA(queryName, 1, 3600)
+	spent := model.SpentTime{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"}
+	p.SetSpentMinutes(queryName, spent, 60)
+	p.AddSpentMinutes(queryName, spent, 60)
+	p.SetEstimationMinutes(queryName, "YT", "YT-100", 120)
+	sprint := model.Sprint{Board: "Backend", Name: "Sprint 1", Columns: map[string]int{"Open": 1}}
+	p.SetSprint(sprint)
+	p.ResetSprint(sprint)
+	project := model.Project{ShortName: "YT", Name: "YouTrack", Leader: "john"}
+	p.EnableProject(project)
+	p.DisableProject(project)
+	p.SetIssuesCount(queryName, "YT", 10)
+	p.SetQueryStatus(queryName, model.SeverityWarning)
+	p.ObserveResolution(queryName, "YT", 120)
+	p.SetTokenExpiry(time.Unix(1546300800, 0))
+	p.TokenRefreshErrorInc("request")
+	p.AddQueuedRequests(2)
+	p.ObserveRequestWait(0.5)
+	p.ObserveHTTPRequest("/api/issues", "200", 0, 1024, 0.5)
+	p.SetCircuitState(1)
+	p.CacheRequestInc(true)
 	p.ErrorInc(queryName, e.New("some error"))
 }
 
@@ -39,6 +65,7 @@ func TestPrometheusMetrics_EnableMonitoring(t *testing.T) {
 
 	type testTableData struct {
 		queryName  string
+		project    string
 		issue      model.Issue
 		expectFunc func(m *MockgaugeIniter)
 	}
@@ -46,13 +73,15 @@ func TestPrometheusMetrics_EnableMonitoring(t *testing.T) {
 	testTable := []testTableData{
 		{
 			queryName: "test query",
+			project:   "YT",
 			issue: model.Issue{
 				ID:    "YT-100",
 				Title: "Test issue",
+				URL:   "https://www.test.com/issue/YT-100",
 			},
 			expectFunc: func(m *MockgaugeIniter) {
 				gauge := NewMockGauge(ctrl)
-				m.EXPECT().WithLabelValues("test query", "YT-100", "Test issue").Return(gauge)
+				m.EXPECT().WithLabelValues("test query", "YT", "YT-100", "Test issue", "https://www.test.com/issue/YT-100").Return(gauge)
 				gauge.EXPECT().Set(float64(1))
 			},
 		},
@@ -60,7 +89,7 @@ func TestPrometheusMetrics_EnableMonitoring(t *testing.T) {
 
 	for _, testUnit := range testTable {
 		testUnit.expectFunc(issues)
-		prometheus.EnableMonitoring(testUnit.queryName, testUnit.issue)
+		prometheus.EnableMonitoring(testUnit.queryName, testUnit.project, testUnit.issue)
 	}
 }
 
@@ -75,6 +104,7 @@ func TestPrometheusMetrics_DisableMonitoring(t *testing.T) {
 
 	type testTableData struct {
 		queryName  string
+		project    string
 		issue      model.Issue
 		expectFunc func(gi *MockgaugeIniter)
 	}
@@ -85,10 +115,11 @@ func TestPrometheusMetrics_DisableMonitoring(t *testing.T) {
 			issue: model.Issue{
 				ID:    "YT-100",
 				Title: "Test issue",
+				URL:   "https://www.test.com/issue/YT-100",
 			},
 			expectFunc: func(gi *MockgaugeIniter) {
 				gauge := NewMockGauge(ctrl)
-				gi.EXPECT().WithLabelValues("test query", "YT-100", "Test issue").Return(gauge)
+				gi.EXPECT().WithLabelValues("test query", "", "YT-100", "Test issue", "https://www.test.com/issue/YT-100").Return(gauge)
 				gauge.EXPECT().Set(float64(0))
 			},
 		},
@@ -96,7 +127,706 @@ func TestPrometheusMetrics_DisableMonitoring(t *testing.T) {
 
 	for _, testUnit := range testTable {
 		testUnit.expectFunc(issues)
-		prometheus.DisableMonitoring(testUnit.queryName, testUnit.issue)
+		prometheus.DisableMonitoring(testUnit.queryName, testUnit.project, testUnit.issue)
+	}
+}
+
+func TestPrometheusMetrics_EnableInfo(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	info := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{info: info}
+
+	type testTableData struct {
+		queryName  string
+		issue      model.Issue
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "test query",
+			issue: model.Issue{
+				ID:    "YT-100",
+				Title: "Test issue",
+				URL:   "https://www.test.com/issue/YT-100",
+			},
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("test query", "YT-100", "Test issue", "https://www.test.com/issue/YT-100").Return(gauge)
+				gauge.EXPECT().Set(float64(1))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(info)
+		prometheus.EnableInfo(testUnit.queryName, testUnit.issue)
+	}
+}
+
+func TestPrometheusMetrics_DisableInfo(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	info := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{info: info}
+
+	type testTableData struct {
+		queryName  string
+		issue      model.Issue
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "test query",
+			issue: model.Issue{
+				ID:    "YT-100",
+				Title: "Test issue",
+				URL:   "https://www.test.com/issue/YT-100",
+			},
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("test query", "YT-100", "Test issue", "https://www.test.com/issue/YT-100").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(info)
+		prometheus.DisableInfo(testUnit.queryName, testUnit.issue)
+	}
+}
+
+func TestPrometheusMetrics_SetSLABreached(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	breached := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{sla: slaGauges{breached: breached}}
+
+	type testTableData struct {
+		tcase      string
+		breached   bool
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:    "breached",
+			breached: true,
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("critical", "YT", "YT-100").Return(gauge)
+				gauge.EXPECT().Set(float64(1))
+			},
+		},
+		{
+			tcase:    "not breached",
+			breached: false,
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("critical", "YT", "YT-100").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(breached)
+		prometheus.SetSLABreached("critical", "YT", "YT-100", testUnit.breached)
+	}
+}
+
+func TestPrometheusMetrics_SetSLA(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	breachedCount := NewMockgaugeIniter(ctrl)
+	timeToBreach := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{sla: slaGauges{breachedCount: breachedCount, timeToBreach: timeToBreach}}
+
+	gauge := NewMockGauge(ctrl)
+	breachedCount.EXPECT().WithLabelValues("critical").Return(gauge)
+	gauge.EXPECT().Set(float64(2))
+	gauge = NewMockGauge(ctrl)
+	timeToBreach.EXPECT().WithLabelValues("critical").Return(gauge)
+	gauge.EXPECT().Set(float64(3600))
+
+	prometheus.SetSLA("critical", 2, 3600)
+}
+
+func TestPrometheusMetrics_SetSpentMinutes(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	spent := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{spent: spent}
+
+	type testTableData struct {
+		queryName  string
+		spent      model.SpentTime
+		minutes    int
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "test query",
+			spent:     model.SpentTime{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"},
+			minutes:   90,
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("test query", "YT", "YT-100", "john", "Development").Return(gauge)
+				gauge.EXPECT().Set(float64(90))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(spent)
+		prometheus.SetSpentMinutes(testUnit.queryName, testUnit.spent, testUnit.minutes)
+	}
+}
+
+func TestPrometheusMetrics_AddSpentMinutes(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	spentTotal := NewMockcounterIniter(ctrl)
+	prometheus := &Metrics{spentTotal: spentTotal}
+
+	type testTableData struct {
+		queryName  string
+		spent      model.SpentTime
+		minutes    int
+		expectFunc func(ci *MockcounterIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "test query",
+			spent:     model.SpentTime{IssueID: "YT-100", Project: "YT", Author: "john", Type: "Development"},
+			minutes:   30,
+			expectFunc: func(ci *MockcounterIniter) {
+				counter := NewMockCounter(ctrl)
+				ci.EXPECT().WithLabelValues("test query", "YT", "john", "Development").Return(counter)
+				counter.EXPECT().Add(float64(30))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(spentTotal)
+		prometheus.AddSpentMinutes(testUnit.queryName, testUnit.spent, testUnit.minutes)
+	}
+}
+
+func TestPrometheusMetrics_SetEstimationMinutes(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	estimation := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{estimation: estimation}
+
+	type testTableData struct {
+		queryName  string
+		project    string
+		issueID    string
+		minutes    int
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "test query",
+			project:   "YT",
+			issueID:   "YT-100",
+			minutes:   480,
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("test query", "YT", "YT-100").Return(gauge)
+				gauge.EXPECT().Set(float64(480))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(estimation)
+		prometheus.SetEstimationMinutes(testUnit.queryName, testUnit.project, testUnit.issueID, testUnit.minutes)
+	}
+}
+
+func TestPrometheusMetrics_SetSprint(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	issues := NewMockgaugeIniter(ctrl)
+	remaining := NewMockgaugeIniter(ctrl)
+	start := NewMockgaugeIniter(ctrl)
+	finish := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{sprint: sprintMetrics{issues: issues, remaining: remaining, start: start, finish: finish}}
+
+	type testTableData struct {
+		tcase      string
+		sprint     model.Sprint
+		expectFunc func()
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "sprint with dates",
+			sprint: model.Sprint{
+				Board:                      "Backend",
+				Name:                       "Sprint 5",
+				Start:                      time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				Finish:                     time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC),
+				Columns:                    map[string]int{"Open": 2, "Done": 3},
+				RemainingEstimationMinutes: 90,
+			},
+			expectFunc: func() {
+				gauge := NewMockGauge(ctrl)
+				issues.EXPECT().WithLabelValues("Backend", "Sprint 5", "Open").Return(gauge)
+				gauge.EXPECT().Set(float64(2))
+				gauge = NewMockGauge(ctrl)
+				issues.EXPECT().WithLabelValues("Backend", "Sprint 5", "Done").Return(gauge)
+				gauge.EXPECT().Set(float64(3))
+				gauge = NewMockGauge(ctrl)
+				remaining.EXPECT().WithLabelValues("Backend", "Sprint 5").Return(gauge)
+				gauge.EXPECT().Set(float64(90))
+				gauge = NewMockGauge(ctrl)
+				start.EXPECT().WithLabelValues("Backend", "Sprint 5").Return(gauge)
+				gauge.EXPECT().Set(float64(1546300800))
+				gauge = NewMockGauge(ctrl)
+				finish.EXPECT().WithLabelValues("Backend", "Sprint 5").Return(gauge)
+				gauge.EXPECT().Set(float64(1547510400))
+			},
+		},
+		{
+			tcase: "sprint without dates",
+			sprint: model.Sprint{
+				Board: "Backend",
+				Name:  "Backlog",
+			},
+			expectFunc: func() {
+				gauge := NewMockGauge(ctrl)
+				remaining.EXPECT().WithLabelValues("Backend", "Backlog").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+				gauge = NewMockGauge(ctrl)
+				start.EXPECT().WithLabelValues("Backend", "Backlog").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+				gauge = NewMockGauge(ctrl)
+				finish.EXPECT().WithLabelValues("Backend", "Backlog").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc()
+		prometheus.SetSprint(testUnit.sprint)
+	}
+}
+
+func TestPrometheusMetrics_ResetSprint(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	issues := NewMockgaugeIniter(ctrl)
+	remaining := NewMockgaugeIniter(ctrl)
+	start := NewMockgaugeIniter(ctrl)
+	finish := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{sprint: sprintMetrics{issues: issues, remaining: remaining, start: start, finish: finish}}
+
+	type testTableData struct {
+		sprint     model.Sprint
+		expectFunc func()
+	}
+
+	testTable := []testTableData{
+		{
+			sprint: model.Sprint{
+				Board:                      "Backend",
+				Name:                       "Sprint 5",
+				Start:                      time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				Finish:                     time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC),
+				Columns:                    map[string]int{"Open": 2},
+				RemainingEstimationMinutes: 90,
+			},
+			expectFunc: func() {
+				gauge := NewMockGauge(ctrl)
+				issues.EXPECT().WithLabelValues("Backend", "Sprint 5", "Open").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+				gauge = NewMockGauge(ctrl)
+				remaining.EXPECT().WithLabelValues("Backend", "Sprint 5").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+				gauge = NewMockGauge(ctrl)
+				start.EXPECT().WithLabelValues("Backend", "Sprint 5").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+				gauge = NewMockGauge(ctrl)
+				finish.EXPECT().WithLabelValues("Backend", "Sprint 5").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc()
+		prometheus.ResetSprint(testUnit.sprint)
+	}
+}
+
+func TestPrometheusMetrics_EnableProject(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	project := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{project: project}
+
+	type testTableData struct {
+		project    model.Project
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			project: model.Project{ShortName: "YT", Name: "YouTrack", Leader: "john", Archived: true},
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("YT", "YouTrack", "john", "true").Return(gauge)
+				gauge.EXPECT().Set(float64(1))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(project)
+		prometheus.EnableProject(testUnit.project)
+	}
+}
+
+func TestPrometheusMetrics_DisableProject(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	project := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{project: project}
+
+	type testTableData struct {
+		project    model.Project
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			project: model.Project{ShortName: "YT", Name: "YouTrack", Leader: "john"},
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("YT", "YouTrack", "john", "false").Return(gauge)
+				gauge.EXPECT().Set(float64(0))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(project)
+		prometheus.DisableProject(testUnit.project)
+	}
+}
+
+func TestPrometheusMetrics_SetIssuesCount(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	count := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{count: count}
+
+	type testTableData struct {
+		queryName  string
+		project    string
+		count      int
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "project_YT",
+			project:   "YT",
+			count:     42,
+			expectFunc: func(gi *MockgaugeIniter) {
+				gauge := NewMockGauge(ctrl)
+				gi.EXPECT().WithLabelValues("project_YT", "YT").Return(gauge)
+				gauge.EXPECT().Set(float64(42))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(count)
+		prometheus.SetIssuesCount(testUnit.queryName, testUnit.project, testUnit.count)
+	}
+}
+
+func TestPrometheusMetrics_SetQueryStatus(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	status := NewMockgaugeIniter(ctrl)
+	prometheus := &Metrics{status: status}
+
+	type testTableData struct {
+		tcase      string
+		severity   string
+		expectFunc func(gi *MockgaugeIniter)
+	}
+
+	expectStatus := func(gi *MockgaugeIniter, ok, warning, critical float64) {
+		gauge := NewMockGauge(ctrl)
+		gi.EXPECT().WithLabelValues("critical bugs", "ok").Return(gauge)
+		gauge.EXPECT().Set(ok)
+		gauge = NewMockGauge(ctrl)
+		gi.EXPECT().WithLabelValues("critical bugs", "warning").Return(gauge)
+		gauge.EXPECT().Set(warning)
+		gauge = NewMockGauge(ctrl)
+		gi.EXPECT().WithLabelValues("critical bugs", "critical").Return(gauge)
+		gauge.EXPECT().Set(critical)
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:    "warning",
+			severity: model.SeverityWarning,
+			expectFunc: func(gi *MockgaugeIniter) {
+				expectStatus(gi, 0, 1, 0)
+			},
+		},
+		{
+			tcase:    "reset",
+			severity: "",
+			expectFunc: func(gi *MockgaugeIniter) {
+				expectStatus(gi, 0, 0, 0)
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(status)
+		prometheus.SetQueryStatus("critical bugs", testUnit.severity)
+	}
+}
+
+func TestPrometheusMetrics_ObserveResolution(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	resolution := NewMockobserverIniter(ctrl)
+	prometheus := &Metrics{resolution: resolution}
+
+	type testTableData struct {
+		queryName  string
+		project    string
+		seconds    float64
+		expectFunc func(oi *MockobserverIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			queryName: "bugs",
+			project:   "YT",
+			seconds:   120,
+			expectFunc: func(oi *MockobserverIniter) {
+				observer := NewMockObserver(ctrl)
+				oi.EXPECT().WithLabelValues("bugs", "YT").Return(observer)
+				observer.EXPECT().Observe(float64(120))
+			},
+		},
+		{
+			queryName: "incidents",
+			project:   "QA",
+			seconds:   7200,
+			expectFunc: func(oi *MockobserverIniter) {
+				observer := NewMockObserver(ctrl)
+				oi.EXPECT().WithLabelValues("incidents", "QA").Return(observer)
+				observer.EXPECT().Observe(float64(7200))
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(resolution)
+		prometheus.ObserveResolution(testUnit.queryName, testUnit.project, testUnit.seconds)
+	}
+}
+
+func TestPrometheusMetrics_SetTokenExpiry(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	expiry := NewMockGauge(ctrl)
+	prometheus := &Metrics{token: tokenMetrics{expiry: expiry}}
+
+	expiry.EXPECT().Set(float64(1546300800))
+
+	prometheus.SetTokenExpiry(time.Unix(1546300800, 0))
+}
+
+func TestPrometheusMetrics_TokenRefreshErrorInc(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	refreshErrors := NewMockcounterIniter(ctrl)
+	prometheus := &Metrics{token: tokenMetrics{refreshErrors: refreshErrors}}
+
+	counter := NewMockCounter(ctrl)
+	refreshErrors.EXPECT().WithLabelValues("request").Return(counter)
+	counter.EXPECT().Inc()
+
+	prometheus.TokenRefreshErrorInc("request")
+}
+
+func TestPrometheusMetrics_AddQueuedRequests(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	queued := NewMockGauge(ctrl)
+	prometheus := &Metrics{requests: requestMetrics{queued: queued}}
+
+	queued.EXPECT().Add(float64(-1))
+
+	prometheus.AddQueuedRequests(-1)
+}
+
+func TestPrometheusMetrics_ObserveRequestWait(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	wait := NewMockObserver(ctrl)
+	prometheus := &Metrics{requests: requestMetrics{wait: wait}}
+
+	wait.EXPECT().Observe(0.5)
+
+	prometheus.ObserveRequestWait(0.5)
+}
+
+func TestPrometheusMetrics_ObserveHTTPRequest(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	requestBytes := NewMockcounterIniter(ctrl)
+	responseBytes := NewMockcounterIniter(ctrl)
+	duration := NewMockobserverIniter(ctrl)
+	prometheus := &Metrics{requests: requestMetrics{
+		requestBytes:  requestBytes,
+		responseBytes: responseBytes,
+		duration:      duration,
+	}}
+
+	requestCounter := NewMockCounter(ctrl)
+	requestBytes.EXPECT().WithLabelValues("/api/issues", "200").Return(requestCounter)
+	requestCounter.EXPECT().Add(float64(10))
+
+	responseCounter := NewMockCounter(ctrl)
+	responseBytes.EXPECT().WithLabelValues("/api/issues", "200").Return(responseCounter)
+	responseCounter.EXPECT().Add(float64(1024))
+
+	observer := NewMockObserver(ctrl)
+	duration.EXPECT().WithLabelValues("/api/issues", "200").Return(observer)
+	observer.EXPECT().Observe(0.5)
+
+	prometheus.ObserveHTTPRequest("/api/issues", "200", 10, 1024, 0.5)
+}
+
+func TestPrometheusMetrics_SetCircuitState(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	circuit := NewMockGauge(ctrl)
+	prometheus := &Metrics{circuit: circuit}
+
+	circuit.EXPECT().Set(float64(2))
+
+	prometheus.SetCircuitState(2)
+}
+
+func TestPrometheusMetrics_CacheRequestInc(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cache := NewMockcounterIniter(ctrl)
+	prometheus := &Metrics{cache: cache}
+
+	type testTableData struct {
+		hit        bool
+		expectFunc func(ci *MockcounterIniter)
+	}
+
+	testTable := []testTableData{
+		{
+			hit: true,
+			expectFunc: func(ci *MockcounterIniter) {
+				counter := NewMockCounter(ctrl)
+				ci.EXPECT().WithLabelValues("hit").Return(counter)
+				counter.EXPECT().Inc()
+			},
+		},
+		{
+			hit: false,
+			expectFunc: func(ci *MockcounterIniter) {
+				counter := NewMockCounter(ctrl)
+				ci.EXPECT().WithLabelValues("miss").Return(counter)
+				counter.EXPECT().Inc()
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		testUnit.expectFunc(cache)
+		prometheus.CacheRequestInc(testUnit.hit)
 	}
 }
 
@@ -132,3 +862,19 @@ func TestPrometheusMetrics_ErrorInc(t *testing.T) {
 		prometheus.ErrorInc(testUnit.queryName, testUnit.error)
 	}
 }
+
+func TestPrometheusMetrics_SprintErrorInc(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	errors := NewMockcounterIniter(ctrl)
+	prometheus := &Metrics{sprint: sprintMetrics{errors: errors}}
+
+	counter := NewMockCounter(ctrl)
+	errors.EXPECT().WithLabelValues("Backend", "agile board not found: Backend").Return(counter)
+	counter.EXPECT().Inc()
+
+	prometheus.SprintErrorInc("Backend", e.New("agile board not found: Backend"))
+}
@@ -0,0 +1,111 @@
+package youtrack
+
+import (
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"time"
+)
+
+type apiAgilesResponse []apiAgile
+
+type apiAgile struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	CurrentSprint *struct {
+		ID string `json:"id"`
+	} `json:"currentSprint"`
+	ColumnSettings struct {
+		Field struct {
+			Name string `json:"name"`
+		} `json:"field"`
+		Columns []struct {
+			Presentation string `json:"presentation"`
+			FieldValues  []struct {
+				Name string `json:"name"`
+			} `json:"fieldValues"`
+		} `json:"columns"`
+	} `json:"columnSettings"`
+}
+
+type apiSprint struct {
+	Name   string           `json:"name"`
+	Start  *int64           `json:"start"`
+	Finish *int64           `json:"finish"`
+	Issues []apiSprintIssue `json:"issues"`
+}
+
+type apiSprintIssue struct {
+	Resolved     *int64          `json:"resolved"`
+	CustomFields apiCustomFields `json:"customFields"`
+}
+
+// ToAgileBoards converts agile boards by name, the first board is kept if names are duplicated.
+func (response apiAgilesResponse) ToAgileBoards() map[string]model.AgileBoard {
+	boards := make(map[string]model.AgileBoard, len(response))
+	for _, agile := range response {
+		if _, ok := boards[agile.Name]; !ok {
+			boards[agile.Name] = agile.ToAgileBoard()
+		}
+	}
+	return boards
+}
+
+// ToAgileBoard converts agile board settings.
+func (aa apiAgile) ToAgileBoard() model.AgileBoard {
+	board := model.AgileBoard{
+		ID:          aa.ID,
+		Name:        aa.Name,
+		ColumnField: aa.ColumnSettings.Field.Name,
+		Columns:     make(map[string][]string, len(aa.ColumnSettings.Columns)),
+	}
+	if aa.CurrentSprint != nil {
+		board.CurrentSprintID = aa.CurrentSprint.ID
+	}
+	for _, column := range aa.ColumnSettings.Columns {
+		values := make([]string, 0, len(column.FieldValues))
+		for _, value := range column.FieldValues {
+			values = append(values, value.Name)
+		}
+		board.Columns[column.Presentation] = values
+	}
+	return board
+}
+
+// ToSprint counts sprint issues in agile board columns and sums remaining estimation of unresolved issues.
+func (as apiSprint) ToSprint(board model.AgileBoard, estimationField string) model.Sprint {
+	columns := make(map[string]int, len(board.Columns))
+	valueColumns := make(map[string]string)
+	for column, values := range board.Columns {
+		columns[column] = 0
+		for _, value := range values {
+			valueColumns[value] = column
+		}
+	}
+
+	remaining := 0
+	for _, issue := range as.Issues {
+		if column, ok := valueColumns[issue.CustomFields.ValueName(board.ColumnField)]; ok {
+			columns[column]++
+		}
+
+		if issue.Resolved == nil {
+			remaining += issue.CustomFields.Minutes(estimationField)
+		}
+	}
+
+	return model.Sprint{
+		Board:                      board.Name,
+		Name:                       as.Name,
+		Start:                      timestampToTime(as.Start),
+		Finish:                     timestampToTime(as.Finish),
+		Columns:                    columns,
+		RemainingEstimationMinutes: remaining,
+	}
+}
+
+// timestampToTime converts YouTrack timestamp in milliseconds to time, nil timestamp is converted to zero time.
+func timestampToTime(ms *int64) time.Time {
+	if ms == nil {
+		return time.Time{}
+	}
+	return time.Unix(0, *ms*int64(time.Millisecond)).UTC()
+}
@@ -0,0 +1,110 @@
+package youtrack
+
+import (
+	"encoding/json"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+const testAgile = `{
+    "id": "108-1",
+    "name": "Backend",
+    "currentSprint": {"id": "109-5"},
+    "columnSettings": {
+        "field": {"name": "State"},
+        "columns": [
+            {"presentation": "Open", "fieldValues": [{"name": "Open"}, {"name": "Reopened"}]},
+            {"presentation": "In Progress", "fieldValues": [{"name": "In Progress"}]},
+            {"presentation": "Done", "fieldValues": [{"name": "Fixed"}]}
+        ]
+    }
+}`
+
+func TestApiAgilesResponse_ToAgileBoards(t *testing.T) {
+	t.Parallel()
+
+	var response apiAgilesResponse
+	assert.NoError(t, json.Unmarshal([]byte(`[`+testAgile+`, {"id": "108-2", "name": "Frontend"}, {"id": "108-3", "name": "Frontend"}]`), &response))
+
+	expected := map[string]model.AgileBoard{
+		"Backend": {
+			ID:              "108-1",
+			Name:            "Backend",
+			CurrentSprintID: "109-5",
+			ColumnField:     "State",
+			Columns: map[string][]string{
+				"Open":        {"Open", "Reopened"},
+				"In Progress": {"In Progress"},
+				"Done":        {"Fixed"},
+			},
+		},
+		"Frontend": {ID: "108-2", Name: "Frontend", Columns: map[string][]string{}},
+	}
+
+	assert.Equal(t, expected, response.ToAgileBoards())
+}
+
+func TestApiSprint_ToSprint(t *testing.T) {
+	t.Parallel()
+
+	var agile apiAgile
+	assert.NoError(t, json.Unmarshal([]byte(testAgile), &agile))
+	board := agile.ToAgileBoard()
+
+	type testTableData struct {
+		tcase    string
+		raw      string
+		expected model.Sprint
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "active sprint",
+			raw: `{
+    "name": "Sprint 5",
+    "start": 1546300800000,
+    "finish": 1547510400000,
+    "issues": [
+        {"resolved": null, "customFields": [{"name": "State", "value": {"name": "Open"}}, {"name": "Estimation", "value": {"minutes": 60}}]},
+        {"resolved": null, "customFields": [{"name": "State", "value": {"name": "Reopened"}}, {"name": "Estimation", "value": null}]},
+        {"resolved": null, "customFields": [{"name": "State", "value": {"name": "In Progress"}}, {"name": "Estimation", "value": {"minutes": 30}}]},
+        {"resolved": 1546400000000, "customFields": [{"name": "State", "value": {"name": "Fixed"}}, {"name": "Estimation", "value": {"minutes": 120}}]},
+        {"resolved": null, "customFields": [{"name": "State", "value": {"name": "Won't fix"}}]}
+    ]
+}`,
+			expected: model.Sprint{
+				Board:  "Backend",
+				Name:   "Sprint 5",
+				Start:  time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC),
+				Finish: time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC),
+				Columns: map[string]int{
+					"Open":        2,
+					"In Progress": 1,
+					"Done":        1,
+				},
+				RemainingEstimationMinutes: 90,
+			},
+		},
+		{
+			tcase: "empty sprint without dates",
+			raw:   `{"name": "Backlog", "start": null, "finish": null, "issues": []}`,
+			expected: model.Sprint{
+				Board: "Backend",
+				Name:  "Backlog",
+				Columns: map[string]int{
+					"Open":        0,
+					"In Progress": 0,
+					"Done":        0,
+				},
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		var sprint apiSprint
+		assert.NoError(t, json.Unmarshal([]byte(testUnit.raw), &sprint), testUnit.tcase)
+		assert.Equal(t, testUnit.expected, sprint.ToSprint(board, "Estimation"), testUnit.tcase)
+	}
+}
@@ -0,0 +1,25 @@
+package youtrack
+
+import "fmt"
+
+//go:generate mockgen -source=auth.go -destination=auth_mocks.go -package=youtrack doc github.com/golang/mock/gomock
+
+// Authorizer returns Authorization header value for YouTrack API requests.
+type Authorizer interface {
+	Authorization() (string, error)
+	// Invalidate drops cached authorization rejected by YouTrack and reports whether request may be retried
+	Invalidate() bool
+}
+
+// TokenAuthorizer authorizes requests with permanent token.
+type TokenAuthorizer string
+
+// Authorization returns bearer authorization with permanent token.
+func (t TokenAuthorizer) Authorization() (string, error) {
+	return fmt.Sprintf("Bearer %v", string(t)), nil
+}
+
+// Invalidate does nothing because permanent token can not be renewed.
+func (t TokenAuthorizer) Invalidate() bool {
+	return false
+}
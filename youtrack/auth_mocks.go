@@ -0,0 +1,58 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth.go
+
+// Package youtrack is a generated GoMock package.
+package youtrack
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockAuthorizer is a mock of Authorizer interface
+type MockAuthorizer struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorizerMockRecorder
+}
+
+// MockAuthorizerMockRecorder is the mock recorder for MockAuthorizer
+type MockAuthorizerMockRecorder struct {
+	mock *MockAuthorizer
+}
+
+// NewMockAuthorizer creates a new mock instance
+func NewMockAuthorizer(ctrl *gomock.Controller) *MockAuthorizer {
+	mock := &MockAuthorizer{ctrl: ctrl}
+	mock.recorder = &MockAuthorizerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAuthorizer) EXPECT() *MockAuthorizerMockRecorder {
+	return m.recorder
+}
+
+// Authorization mocks base method
+func (m *MockAuthorizer) Authorization() (string, error) {
+	ret := m.ctrl.Call(m, "Authorization")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorization indicates an expected call of Authorization
+func (mr *MockAuthorizerMockRecorder) Authorization() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorization", reflect.TypeOf((*MockAuthorizer)(nil).Authorization))
+}
+
+// Invalidate mocks base method
+func (m *MockAuthorizer) Invalidate() bool {
+	ret := m.ctrl.Call(m, "Invalidate")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Invalidate indicates an expected call of Invalidate
+func (mr *MockAuthorizerMockRecorder) Invalidate() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Invalidate", reflect.TypeOf((*MockAuthorizer)(nil).Invalidate))
+}
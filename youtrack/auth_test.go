@@ -0,0 +1,13 @@
+package youtrack
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTokenAuthorizer(t *testing.T) {
+	authorization, err := TokenAuthorizer("abc").Authorization()
+	assert.Equal(t, "Bearer abc", authorization)
+	assert.NoError(t, err)
+	assert.False(t, TokenAuthorizer("abc").Invalidate())
+}
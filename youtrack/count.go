@@ -0,0 +1,11 @@
+package youtrack
+
+// apiCountRequest is request of issues count by search query.
+type apiCountRequest struct {
+	Query string `json:"query"`
+}
+
+// apiCountResponse is issues count, it equals -1 while YouTrack is still counting.
+type apiCountResponse struct {
+	Count int `json:"count"`
+}
@@ -0,0 +1,50 @@
+package youtrack
+
+import "encoding/json"
+
+type apiCustomFields []apiCustomField
+
+type apiCustomField struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Minutes returns minutes of period custom field with passed name.
+// Returns 0 if field is not found, not set or is not period field.
+func (fields apiCustomFields) Minutes(name string) int {
+	var value struct {
+		Minutes int `json:"minutes"`
+	}
+	fields.decode(name, &value)
+	return value.Minutes
+}
+
+// ValueName returns value name of single value custom field (state, enum, etc.) with passed name.
+// Returns empty string if field is not found, not set or is not single value field.
+func (fields apiCustomFields) ValueName(name string) string {
+	var value struct {
+		Name string `json:"name"`
+	}
+	fields.decode(name, &value)
+	return value.Name
+}
+
+// Timestamp returns timestamp in milliseconds of date custom field with passed name.
+// Returns nil if field is not found, not set or is not date field.
+func (fields apiCustomFields) Timestamp(name string) *int64 {
+	var value json.Number
+	fields.decode(name, &value)
+	ms, err := value.Int64()
+	if err != nil {
+		return nil
+	}
+	return &ms
+}
+
+func (fields apiCustomFields) decode(name string, value interface{}) {
+	for _, cf := range fields {
+		if cf.Name == name && json.Unmarshal(cf.Value, value) == nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package youtrack
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApiCustomFields_Minutes(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase    string
+		raw      string
+		name     string
+		expected int
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:    "period field",
+			raw:      `[{"name": "Priority", "value": {"name": "Major"}}, {"name": "Estimation", "value": {"minutes": 120}}]`,
+			name:     "Estimation",
+			expected: 120,
+		},
+		{
+			tcase:    "field not set",
+			raw:      `[{"name": "Estimation", "value": null}]`,
+			name:     "Estimation",
+			expected: 0,
+		},
+		{
+			tcase:    "field not found",
+			raw:      `[{"name": "Priority", "value": {"name": "Major"}}]`,
+			name:     "Estimation",
+			expected: 0,
+		},
+		{
+			tcase:    "not period field",
+			raw:      `[{"name": "Estimation", "value": "2h"}]`,
+			name:     "Estimation",
+			expected: 0,
+		},
+	}
+
+	for _, testUnit := range testTable {
+		var fields apiCustomFields
+		assert.NoError(t, json.Unmarshal([]byte(testUnit.raw), &fields), testUnit.tcase)
+		assert.Equal(t, testUnit.expected, fields.Minutes(testUnit.name), testUnit.tcase)
+	}
+}
+
+func TestApiCustomFields_ValueName(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase    string
+		raw      string
+		name     string
+		expected string
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:    "single value field",
+			raw:      `[{"name": "Estimation", "value": {"minutes": 120}}, {"name": "State", "value": {"name": "In Progress"}}]`,
+			name:     "State",
+			expected: "In Progress",
+		},
+		{
+			tcase:    "field not set",
+			raw:      `[{"name": "State", "value": null}]`,
+			name:     "State",
+			expected: "",
+		},
+		{
+			tcase:    "multi value field",
+			raw:      `[{"name": "State", "value": [{"name": "Open"}]}]`,
+			name:     "State",
+			expected: "",
+		},
+	}
+
+	for _, testUnit := range testTable {
+		var fields apiCustomFields
+		assert.NoError(t, json.Unmarshal([]byte(testUnit.raw), &fields), testUnit.tcase)
+		assert.Equal(t, testUnit.expected, fields.ValueName(testUnit.name), testUnit.tcase)
+	}
+}
+
+func TestApiCustomFields_Timestamp(t *testing.T) {
+	t.Parallel()
+
+	timestamp := int64(1546336800000)
+
+	type testTableData struct {
+		tcase    string
+		raw      string
+		name     string
+		expected *int64
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:    "date field",
+			raw:      `[{"name": "State", "value": {"name": "Open"}}, {"name": "Response date", "value": 1546336800000}]`,
+			name:     "Response date",
+			expected: &timestamp,
+		},
+		{
+			tcase:    "field not set",
+			raw:      `[{"name": "Response date", "value": null}]`,
+			name:     "Response date",
+			expected: nil,
+		},
+		{
+			tcase:    "not date field",
+			raw:      `[{"name": "Response date", "value": {"name": "Open"}}]`,
+			name:     "Response date",
+			expected: nil,
+		},
+	}
+
+	for _, testUnit := range testTable {
+		var fields apiCustomFields
+		assert.NoError(t, json.Unmarshal([]byte(testUnit.raw), &fields), testUnit.tcase)
+		assert.Equal(t, testUnit.expected, fields.Timestamp(testUnit.name), testUnit.tcase)
+	}
+}
@@ -0,0 +1,146 @@
+package youtrack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/httpwrap"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:generate mockgen -source=hub.go -destination=hub_mocks.go -package=youtrack doc github.com/golang/mock/gomock
+
+const (
+	hubTokenPath = "api/rest/oauth2/token"
+	// tokenRefreshMargin is time before token expiry when token is refreshed, it is at most half of token lifetime
+	tokenRefreshMargin = time.Minute
+)
+
+// Token refresh error reasons.
+const (
+	refreshErrorRequest    = "request"
+	refreshErrorStatus     = "status"
+	refreshErrorDecode     = "decode"
+	refreshErrorEmptyToken = "empty_token"
+)
+
+type postFormer interface {
+	PostForm(r *httpwrap.Request, form url.Values) ([]byte, error)
+}
+
+type tokenMetricser interface {
+	SetTokenExpiry(expiry time.Time)
+	TokenRefreshErrorInc(reason string)
+}
+
+// HubAuthorizer authorizes requests with access token of Hub service got by OAuth2 client credentials flow.
+// Access token is cached and refreshed before expiry. HubAuthorizer is safe for concurrent use.
+type HubAuthorizer struct {
+	requester postFormer
+	metricser tokenMetricser
+	log       *logging.Logger
+	url       string
+	headers   map[string]string
+	form      url.Values
+	now       func() time.Time
+
+	mu    sync.Mutex
+	token string
+	// refreshAt is zero for token without expiry, it is used until rejected
+	refreshAt time.Time
+}
+
+type hubTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// NewHubAuthorizer creates HubAuthorizer instance, token refreshes are logged with passed logger.
+func NewHubAuthorizer(hubURL, clientID, clientSecret, scope string, requester postFormer, metricser tokenMetricser, log *logging.Logger) *HubAuthorizer {
+	credentials := base64.StdEncoding.EncodeToString([]byte(clientID + ":" + clientSecret))
+	return &HubAuthorizer{
+		requester: requester,
+		metricser: metricser,
+		log:       log,
+		url:       strings.TrimSuffix(hubURL, "/") + "/" + hubTokenPath,
+		headers: map[string]string{
+			"Accept":        "application/json",
+			"Authorization": fmt.Sprintf("Basic %v", credentials),
+		},
+		form: url.Values{
+			"grant_type": {"client_credentials"},
+			"scope":      {scope},
+		},
+		now: time.Now,
+	}
+}
+
+// Authorization returns bearer authorization with cached or refreshed access token.
+func (h *HubAuthorizer) Authorization() (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.token == "" || (!h.refreshAt.IsZero() && !h.now().Before(h.refreshAt)) {
+		reason, err := h.refresh()
+		if err != nil {
+			h.log.Error("hub token refresh failed", "reason", reason, "error", err)
+			h.metricser.TokenRefreshErrorInc(reason)
+			return "", fmt.Errorf("token refresh error: %v", err)
+		}
+	}
+
+	return fmt.Sprintf("Bearer %v", h.token), nil
+}
+
+// Invalidate drops cached access token, so it is refreshed on the next request.
+func (h *HubAuthorizer) Invalidate() bool {
+	h.mu.Lock()
+	h.token = ""
+	h.mu.Unlock()
+	return true
+}
+
+// refresh gets new access token, reason of error is returned for errors metric.
+func (h *HubAuthorizer) refresh() (reason string, err error) {
+	body, err := h.requester.PostForm(&httpwrap.Request{URL: h.url, Headers: h.headers}, h.form)
+	if _, ok := err.(*httpwrap.StatusError); ok {
+		return refreshErrorStatus, err
+	}
+	if err != nil {
+		return refreshErrorRequest, err
+	}
+
+	var response hubTokenResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return refreshErrorDecode, err
+	}
+
+	if response.AccessToken == "" {
+		return refreshErrorEmptyToken, errors.New("empty access token")
+	}
+
+	h.token = response.AccessToken
+	lifetime := time.Duration(response.ExpiresIn) * time.Second
+	h.log.Info("hub token refreshed", "expires_in", lifetime)
+	if lifetime <= 0 {
+		h.refreshAt = time.Time{}
+		return "", nil
+	}
+
+	// Short-lived token is refreshed in the middle of its lifetime instead of each request
+	margin := tokenRefreshMargin
+	if margin > lifetime/2 {
+		margin = lifetime / 2
+	}
+
+	expiry := h.now().Add(lifetime)
+	h.refreshAt = expiry.Add(-margin)
+	h.metricser.SetTokenExpiry(expiry)
+	return "", nil
+}
@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: hub.go
+
+// Package youtrack is a generated GoMock package.
+package youtrack
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	httpwrap "github.com/krpn/youtrack-issues-prometheus-exporter/httpwrap"
+	url "net/url"
+	reflect "reflect"
+	time "time"
+)
+
+// MockpostFormer is a mock of postFormer interface
+type MockpostFormer struct {
+	ctrl     *gomock.Controller
+	recorder *MockpostFormerMockRecorder
+}
+
+// MockpostFormerMockRecorder is the mock recorder for MockpostFormer
+type MockpostFormerMockRecorder struct {
+	mock *MockpostFormer
+}
+
+// NewMockpostFormer creates a new mock instance
+func NewMockpostFormer(ctrl *gomock.Controller) *MockpostFormer {
+	mock := &MockpostFormer{ctrl: ctrl}
+	mock.recorder = &MockpostFormerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockpostFormer) EXPECT() *MockpostFormerMockRecorder {
+	return m.recorder
+}
+
+// PostForm mocks base method
+func (m *MockpostFormer) PostForm(r *httpwrap.Request, form url.Values) ([]byte, error) {
+	ret := m.ctrl.Call(m, "PostForm", r, form)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PostForm indicates an expected call of PostForm
+func (mr *MockpostFormerMockRecorder) PostForm(r, form interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostForm", reflect.TypeOf((*MockpostFormer)(nil).PostForm), r, form)
+}
+
+// MocktokenMetricser is a mock of tokenMetricser interface
+type MocktokenMetricser struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenMetricserMockRecorder
+}
+
+// MocktokenMetricserMockRecorder is the mock recorder for MocktokenMetricser
+type MocktokenMetricserMockRecorder struct {
+	mock *MocktokenMetricser
+}
+
+// NewMocktokenMetricser creates a new mock instance
+func NewMocktokenMetricser(ctrl *gomock.Controller) *MocktokenMetricser {
+	mock := &MocktokenMetricser{ctrl: ctrl}
+	mock.recorder = &MocktokenMetricserMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MocktokenMetricser) EXPECT() *MocktokenMetricserMockRecorder {
+	return m.recorder
+}
+
+// SetTokenExpiry mocks base method
+func (m *MocktokenMetricser) SetTokenExpiry(expiry time.Time) {
+	m.ctrl.Call(m, "SetTokenExpiry", expiry)
+}
+
+// SetTokenExpiry indicates an expected call of SetTokenExpiry
+func (mr *MocktokenMetricserMockRecorder) SetTokenExpiry(expiry interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTokenExpiry", reflect.TypeOf((*MocktokenMetricser)(nil).SetTokenExpiry), expiry)
+}
+
+// TokenRefreshErrorInc mocks base method
+func (m *MocktokenMetricser) TokenRefreshErrorInc(reason string) {
+	m.ctrl.Call(m, "TokenRefreshErrorInc", reason)
+}
+
+// TokenRefreshErrorInc indicates an expected call of TokenRefreshErrorInc
+func (mr *MocktokenMetricserMockRecorder) TokenRefreshErrorInc(reason interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TokenRefreshErrorInc", reflect.TypeOf((*MocktokenMetricser)(nil).TokenRefreshErrorInc), reason)
+}
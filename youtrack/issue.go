@@ -5,8 +5,6 @@ import (
 	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
 )
 
-type apiResponse []apiIssue
-
 type apiIssue struct {
 	Project struct {
 		ShortName string `json:"shortName"`
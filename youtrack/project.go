@@ -0,0 +1,23 @@
+package youtrack
+
+import "github.com/krpn/youtrack-issues-prometheus-exporter/model"
+
+type apiProjectsResponse []apiProject
+
+type apiProject struct {
+	ShortName string `json:"shortName"`
+	Name      string `json:"name"`
+	Leader    struct {
+		Login string `json:"login"`
+	} `json:"leader"`
+	Archived bool `json:"archived"`
+}
+
+func (ap apiProject) ToProject() model.Project {
+	return model.Project{
+		ShortName: ap.ShortName,
+		Name:      ap.Name,
+		Leader:    ap.Leader.Login,
+		Archived:  ap.Archived,
+	}
+}
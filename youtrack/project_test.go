@@ -0,0 +1,45 @@
+package youtrack
+
+import (
+	"encoding/json"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApiProject_ToProject(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase    string
+		raw      string
+		expected model.Project
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "with leader",
+			raw:   `{"shortName": "YT", "name": "YouTrack", "leader": {"login": "john"}, "archived": true}`,
+			expected: model.Project{
+				ShortName: "YT",
+				Name:      "YouTrack",
+				Leader:    "john",
+				Archived:  true,
+			},
+		},
+		{
+			tcase: "without leader",
+			raw:   `{"shortName": "YT", "name": "YouTrack", "leader": null, "archived": false}`,
+			expected: model.Project{
+				ShortName: "YT",
+				Name:      "YouTrack",
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		var project apiProject
+		assert.NoError(t, json.Unmarshal([]byte(testUnit.raw), &project), testUnit.tcase)
+		assert.Equal(t, testUnit.expected, project.ToProject(), testUnit.tcase)
+	}
+}
@@ -0,0 +1,20 @@
+package youtrack
+
+import "github.com/krpn/youtrack-issues-prometheus-exporter/model"
+
+type apiResolutionResponse []apiResolutionIssue
+
+type apiResolutionIssue struct {
+	apiIssue
+	Created  *int64 `json:"created"`
+	Resolved *int64 `json:"resolved"`
+}
+
+func (ai apiResolutionIssue) ToResolution() model.Resolution {
+	return model.Resolution{
+		IssueID:  ai.ID(),
+		Project:  ai.Project.ShortName,
+		Created:  timestampToTime(ai.Created),
+		Resolved: timestampToTime(ai.Resolved),
+	}
+}
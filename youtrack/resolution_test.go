@@ -0,0 +1,47 @@
+package youtrack
+
+import (
+	"encoding/json"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestApiResolutionIssue_ToResolution(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase    string
+		raw      string
+		expected model.Resolution
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "resolved",
+			raw:   `{"project": {"shortName": "YT"}, "numberInProject": 100, "summary": "Test issue", "created": 1546336800000, "resolved": 1546344000000}`,
+			expected: model.Resolution{
+				IssueID:  "YT-100",
+				Project:  "YT",
+				Created:  time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+				Resolved: time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			tcase: "unresolved",
+			raw:   `{"project": {"shortName": "YT"}, "numberInProject": 100, "summary": "Test issue", "created": 1546336800000, "resolved": null}`,
+			expected: model.Resolution{
+				IssueID: "YT-100",
+				Project: "YT",
+				Created: time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		var issue apiResolutionIssue
+		assert.NoError(t, json.Unmarshal([]byte(testUnit.raw), &issue), testUnit.tcase)
+		assert.Equal(t, testUnit.expected, issue.ToResolution(), testUnit.tcase)
+	}
+}
@@ -0,0 +1,26 @@
+package youtrack
+
+import "github.com/krpn/youtrack-issues-prometheus-exporter/model"
+
+type apiSLAResponse []apiSLAIssue
+
+type apiSLAIssue struct {
+	apiIssue
+	Created      *int64          `json:"created"`
+	CustomFields apiCustomFields `json:"customFields"`
+}
+
+// ToSLAIssue converts issue, SLA start is taken from date custom field with passed name or from creation time if name is empty.
+func (ai apiSLAIssue) ToSLAIssue(startField string) model.SLAIssue {
+	start := ai.Created
+	if startField != "" {
+		start = ai.CustomFields.Timestamp(startField)
+	}
+
+	return model.SLAIssue{
+		Issue:   ai.ToIssue(),
+		Project: ai.Project.ShortName,
+		Created: timestampToTime(ai.Created),
+		Start:   timestampToTime(start),
+	}
+}
@@ -0,0 +1,66 @@
+package youtrack
+
+import (
+	"encoding/json"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestApiSLAIssue_ToSLAIssue(t *testing.T) {
+	t.Parallel()
+
+	const raw = `{
+    "project": {"shortName": "YT"},
+    "numberInProject": 100,
+    "summary": "Test issue",
+    "created": 1546336800000,
+    "customFields": [{"name": "Response date", "value": 1546344000000}, {"name": "Empty date", "value": null}]
+}`
+
+	type testTableData struct {
+		tcase      string
+		startField string
+		expected   model.SLAIssue
+	}
+
+	testTable := []testTableData{
+		{
+			tcase:      "created",
+			startField: "",
+			expected: model.SLAIssue{
+				Issue:   model.Issue{ID: "YT-100", Title: "Test issue"},
+				Project: "YT",
+				Created: time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+				Start:   time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			tcase:      "custom field",
+			startField: "Response date",
+			expected: model.SLAIssue{
+				Issue:   model.Issue{ID: "YT-100", Title: "Test issue"},
+				Project: "YT",
+				Created: time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+				Start:   time.Date(2019, 1, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			tcase:      "custom field not set",
+			startField: "Empty date",
+			expected: model.SLAIssue{
+				Issue:   model.Issue{ID: "YT-100", Title: "Test issue"},
+				Project: "YT",
+				Created: time.Date(2019, 1, 1, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	var issue apiSLAIssue
+	assert.NoError(t, json.Unmarshal([]byte(raw), &issue))
+
+	for _, testUnit := range testTable {
+		assert.Equal(t, testUnit.expected, issue.ToSLAIssue(testUnit.startField), testUnit.tcase)
+	}
+}
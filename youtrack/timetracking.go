@@ -0,0 +1,45 @@
+package youtrack
+
+import "github.com/krpn/youtrack-issues-prometheus-exporter/model"
+
+type apiTimeTrackingResponse []apiTimeTrackingIssue
+
+type apiTimeTrackingIssue struct {
+	apiIssue
+	TimeTracking struct {
+		WorkItems []apiWorkItem `json:"workItems"`
+	} `json:"timeTracking"`
+	CustomFields apiCustomFields `json:"customFields"`
+}
+
+type apiWorkItem struct {
+	ID       string `json:"id"`
+	Duration struct {
+		Minutes int `json:"minutes"`
+	} `json:"duration"`
+	Author struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Type struct {
+		Name string `json:"name"`
+	} `json:"type"`
+}
+
+func (ai apiTimeTrackingIssue) ToTimeTracking(estimationField string) model.TimeTracking {
+	workItems := make([]model.WorkItem, 0, len(ai.TimeTracking.WorkItems))
+	for _, wi := range ai.TimeTracking.WorkItems {
+		workItems = append(workItems, model.WorkItem{
+			ID:      wi.ID,
+			Author:  wi.Author.Login,
+			Type:    wi.Type.Name,
+			Minutes: wi.Duration.Minutes,
+		})
+	}
+
+	return model.TimeTracking{
+		IssueID:           ai.ID(),
+		Project:           ai.Project.ShortName,
+		EstimationMinutes: ai.CustomFields.Minutes(estimationField),
+		WorkItems:         workItems,
+	}
+}
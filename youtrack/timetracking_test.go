@@ -0,0 +1,66 @@
+package youtrack
+
+import (
+	"encoding/json"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestApiTimeTrackingIssue_ToTimeTracking(t *testing.T) {
+	t.Parallel()
+
+	type testTableData struct {
+		tcase    string
+		raw      string
+		expected model.TimeTracking
+	}
+
+	testTable := []testTableData{
+		{
+			tcase: "with work items",
+			raw: `{
+    "project": {"shortName": "YT"},
+    "summary": "Test issue",
+    "numberInProject": 100,
+    "timeTracking": {
+        "workItems": [
+            {"id": "1-1", "duration": {"minutes": 60}, "author": {"login": "john"}, "type": {"name": "Development"}},
+            {"id": "1-2", "duration": {"minutes": 30}, "author": {"login": "jane"}, "type": null}
+        ]
+    },
+    "customFields": [{"name": "Estimation", "value": {"minutes": 480}}]
+}`,
+			expected: model.TimeTracking{
+				IssueID:           "YT-100",
+				Project:           "YT",
+				EstimationMinutes: 480,
+				WorkItems: []model.WorkItem{
+					{ID: "1-1", Author: "john", Type: "Development", Minutes: 60},
+					{ID: "1-2", Author: "jane", Type: "", Minutes: 30},
+				},
+			},
+		},
+		{
+			tcase: "time tracking disabled",
+			raw: `{
+    "project": {"shortName": "YT"},
+    "summary": "Test issue",
+    "numberInProject": 100,
+    "timeTracking": null,
+    "customFields": []
+}`,
+			expected: model.TimeTracking{
+				IssueID:   "YT-100",
+				Project:   "YT",
+				WorkItems: []model.WorkItem{},
+			},
+		},
+	}
+
+	for _, testUnit := range testTable {
+		var issue apiTimeTrackingIssue
+		assert.NoError(t, json.Unmarshal([]byte(testUnit.raw), &issue), testUnit.tcase)
+		assert.Equal(t, testUnit.expected, issue.ToTimeTracking("Estimation"), testUnit.tcase)
+	}
+}
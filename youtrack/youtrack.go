@@ -3,75 +3,298 @@ package youtrack
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/httpwrap"
+	"github.com/krpn/youtrack-issues-prometheus-exporter/logging"
 	"github.com/krpn/youtrack-issues-prometheus-exporter/model"
+	"io"
+	"net/http"
 	"net/url"
+	"strings"
+	"time"
 )
 
 //go:generate mockgen -source=youtrack.go -destination=youtrack_mocks.go -package=youtrack doc github.com/golang/mock/gomock
 
-const apiPath = "api/issues"
+const (
+	apiPath     = "api/issues"
+	issuePath   = "issue/"
+	issueFields = "project(shortName),numberInProject,summary"
+
+	timeTrackingFields = issueFields +
+		",timeTracking(workItems(id,duration(minutes),author(login),type(name)))" +
+		",customFields(name,value(minutes))"
+
+	resolutionFields = issueFields + ",created,resolved"
+	slaFields        = issueFields + ",created,customFields(name,value)"
+
+	agilesPath   = "api/agiles"
+	agileFields  = "id,name,currentSprint(id),columnSettings(field(name),columns(presentation,fieldValues(name)))"
+	sprintFields = "name,start,finish,issues(resolved,customFields(name,value(name,minutes)))"
+
+	projectsPath   = "api/admin/projects"
+	projectsFields = "shortName,name,leader(login),archived"
+
+	countPath   = "api/issuesGetter/count"
+	countFields = "count"
+	// countRetries limits retries of issues count which is not ready yet
+	countRetries    = 5
+	countRetryDelay = 500 * time.Millisecond
+)
 
 type makeRequester interface {
-	MakeRequest(url string, headers map[string]string) ([]byte, error)
+	Do(r *httpwrap.Request) (*httpwrap.Response, error)
+	StreamIfChanged(r *httpwrap.Request, hash string, read func(body io.Reader) error) (string, error)
+	Stream(r *httpwrap.Request, read func(body io.Reader) error) error
 }
 
 // YouTrack describes simple YouTrack API client.
+// YouTrack is safe for concurrent use: its fields are never modified after creation and authorizer is concurrency-safe.
 type YouTrack struct {
-	requester makeRequester
-	url       url.URL
-	getParams url.Values
-	headers   map[string]string
+	requester  makeRequester
+	authorizer Authorizer
+	url        url.URL
+	baseURL    url.URL
+	headers    map[string]string
+	log        *logging.Logger
+	sleep      func(time.Duration)
 }
 
 // New creates YouTrack instance.
-func New(endpoint, token string, requester makeRequester) (*YouTrack, error) {
+// Requests are authorized with permanent token or Hub service credentials depending on passed authorizer.
+func New(endpoint string, authorizer Authorizer, requester makeRequester) (*YouTrack, error) {
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
 	}
-	u.Path = apiPath
 
-	getParams := url.Values{}
-	getParams.Set("fields", "project(shortName),numberInProject,summary")
+	// YouTrack may be installed under subpath, so all paths are relative to endpoint path.
+	// Credentials of endpoint are dropped, so they are never exported in issue URLs.
+	baseURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+	if !strings.HasSuffix(baseURL.Path, "/") {
+		baseURL.Path += "/"
+	}
 
 	return &YouTrack{
-		requester: requester,
-		url:       *u,
-		getParams: getParams,
+		requester:  requester,
+		authorizer: authorizer,
+		url:        *baseURL.ResolveReference(&url.URL{Path: apiPath}),
+		baseURL:    baseURL,
 		headers: map[string]string{
-			"Accept":        "application/json",
-			"Content-Type":  "application/json",
-			"Authorization": fmt.Sprintf("Bearer %v", token),
+			"Accept":       "application/json",
+			"Content-Type": "application/json",
 		},
+		sleep: time.Sleep,
 	}, nil
 }
 
+// WithLogger returns copy of YouTrack which logs with passed logger.
+func (yt *YouTrack) WithLogger(log *logging.Logger) *YouTrack {
+	wrap := *yt
+	wrap.log = log
+	return &wrap
+}
+
 // GetIssues gets issues for passed query string.
+// Response is decoded incrementally, so memory is not spent on the whole response body.
 func (yt *YouTrack) GetIssues(query string) (issues map[string]model.Issue, err error) {
-	u := yt.getAPIURL(query)
+	u := yt.getAPIURL(query, issueFields)
+	_, err = yt.authorized(func(headers map[string]string) ([]byte, error) {
+		return nil, yt.requester.Stream(&httpwrap.Request{URL: u, Headers: headers}, func(body io.Reader) error {
+			issues, err = yt.decodeIssues(body)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
 
-	body, err := yt.requester.MakeRequest(u, yt.headers)
+// GetIssuesIfChanged gets issues for passed query string if response hash differs from passed one.
+// Response is decoded incrementally while it is hashed. Changed is false and issues are nil if response has not changed.
+func (yt *YouTrack) GetIssuesIfChanged(query, hash string) (issues map[string]model.Issue, newHash string, changed bool, err error) {
+	u := yt.getAPIURL(query, issueFields)
+	_, err = yt.authorized(func(headers map[string]string) ([]byte, error) {
+		newHash, err = yt.requester.StreamIfChanged(&httpwrap.Request{URL: u, Headers: headers}, hash, func(body io.Reader) error {
+			issues, err = yt.decodeIssues(body)
+			return err
+		})
+		return nil, err
+	})
+	if err == httpwrap.ErrNotModified {
+		return nil, newHash, false, nil
+	}
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return issues, newHash, true, nil
+}
+
+// GetIssuesCount gets issues count for passed query string, issues themselves are not downloaded.
+// Count is requested again while YouTrack is still counting.
+func (yt *YouTrack) GetIssuesCount(query string) (count int, err error) {
+	u := yt.getURL(countPath, url.Values{"fields": {countFields}})
+	for retry := 0; ; retry++ {
+		var response apiCountResponse
+		err = yt.do(httpwrap.Request{Method: "POST", URL: u, JSON: apiCountRequest{Query: query}}, &response)
+		if err != nil {
+			return 0, err
+		}
+		if response.Count >= 0 {
+			return response.Count, nil
+		}
+		if retry == countRetries {
+			return 0, fmt.Errorf("issues count is not ready after %v retries", countRetries)
+		}
+		yt.log.Debug("issues count is not ready, retrying", "query", query, "retry", retry+1, "delay", countRetryDelay)
+		yt.sleep(countRetryDelay)
+	}
+}
+
+// decodeIssues decodes issues array element by element, so only resulting issues are kept in memory.
+func (yt *YouTrack) decodeIssues(r io.Reader) (map[string]model.Issue, error) {
+	decoder := json.NewDecoder(r)
+
+	token, err := decoder.Token()
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
 	if err != nil {
 		return nil, err
 	}
+	if token != json.Delim('[') {
+		return nil, fmt.Errorf("unexpected response: %v instead of issues array", token)
+	}
+
+	issues := make(map[string]model.Issue)
+	for decoder.More() {
+		var ai apiIssue
+		err = decoder.Decode(&ai)
+		if err != nil {
+			return nil, err
+		}
+
+		issue := ai.ToIssue()
+		issue.URL = yt.IssueURL(issue.ID)
+		issues[issue.FullID()] = issue
+	}
 
-	response := make(apiResponse, 0)
-	err = json.Unmarshal(body, &response)
+	// closing bracket
+	_, err = decoder.Token()
 	if err != nil {
 		return nil, err
 	}
 
-	issues = make(map[string]model.Issue, len(response))
+	return issues, nil
+}
+
+// GetTimeTracking gets time tracking data of issues for passed query string.
+// Estimation is taken from period custom field with passed name.
+func (yt *YouTrack) GetTimeTracking(query, estimationField string) (trackings []model.TimeTracking, err error) {
+	response := make(apiTimeTrackingResponse, 0)
+	err = yt.get(yt.getAPIURL(query, timeTrackingFields), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	trackings = make([]model.TimeTracking, 0, len(response))
 	for _, ai := range response {
-		issue := ai.ToIssue()
+		trackings = append(trackings, ai.ToTimeTracking(estimationField))
+	}
+
+	return trackings, nil
+}
+
+// GetSLAIssues gets issues with SLA start time for passed query string.
+// SLA start is taken from date custom field with passed name or from creation time if name is empty.
+func (yt *YouTrack) GetSLAIssues(query, startField string) (issues map[string]model.SLAIssue, err error) {
+	response := make(apiSLAResponse, 0)
+	err = yt.get(yt.getAPIURL(query, slaFields), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	issues = make(map[string]model.SLAIssue, len(response))
+	for _, ai := range response {
+		issue := ai.ToSLAIssue(startField)
+		issue.URL = yt.IssueURL(issue.ID)
 		issues[issue.FullID()] = issue
 	}
 
 	return issues, nil
 }
 
-func (yt *YouTrack) getAPIURL(query string) string {
-	params := yt.getParams
+// GetResolutions gets creation and resolution time of issues for passed query string.
+func (yt *YouTrack) GetResolutions(query string) (resolutions []model.Resolution, err error) {
+	response := make(apiResolutionResponse, 0)
+	err = yt.get(yt.getAPIURL(query, resolutionFields), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	resolutions = make([]model.Resolution, 0, len(response))
+	for _, ai := range response {
+		resolutions = append(resolutions, ai.ToResolution())
+	}
+
+	return resolutions, nil
+}
+
+// GetAgileBoards gets all agile boards by name.
+func (yt *YouTrack) GetAgileBoards() (boards map[string]model.AgileBoard, err error) {
+	agiles := make(apiAgilesResponse, 0)
+	err = yt.get(yt.getURL(agilesPath, url.Values{"fields": {agileFields}}), &agiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return agiles.ToAgileBoards(), nil
+}
+
+// GetSprint gets current sprint of passed agile board.
+// Remaining estimation is taken from period custom field with passed name.
+func (yt *YouTrack) GetSprint(board model.AgileBoard, estimationField string) (sprint model.Sprint, err error) {
+	if board.CurrentSprintID == "" {
+		return sprint, fmt.Errorf("agile board has no current sprint: %v", board.Name)
+	}
+
+	var response apiSprint
+	path := fmt.Sprintf("%v/%v/sprints/%v", agilesPath, board.ID, board.CurrentSprintID)
+	err = yt.get(yt.getURL(path, url.Values{"fields": {sprintFields}}), &response)
+	if err != nil {
+		return sprint, err
+	}
+
+	return response.ToSprint(board, estimationField), nil
+}
+
+// GetProjects gets all projects.
+func (yt *YouTrack) GetProjects() (projects []model.Project, err error) {
+	response := make(apiProjectsResponse, 0)
+	err = yt.get(yt.getURL(projectsPath, url.Values{"fields": {projectsFields}}), &response)
+	if err != nil {
+		return nil, err
+	}
+
+	projects = make([]model.Project, 0, len(response))
+	for _, ap := range response {
+		projects = append(projects, ap.ToProject())
+	}
+
+	return projects, nil
+}
+
+// IssueURL returns web URL of issue with passed ID.
+func (yt *YouTrack) IssueURL(id string) string {
+	return yt.baseURL.ResolveReference(&url.URL{Path: issuePath + id}).String()
+}
+
+// getAPIURL builds request URL, parameters are created for each request to avoid shared state.
+func (yt *YouTrack) getAPIURL(query, fields string) string {
+	params := url.Values{}
+	params.Set("fields", fields)
 	params.Set("query", query)
 
 	u := yt.url
@@ -79,3 +302,57 @@ func (yt *YouTrack) getAPIURL(query string) string {
 
 	return u.String()
 }
+
+// getURL builds request URL for API path relative to endpoint.
+func (yt *YouTrack) getURL(path string, params url.Values) string {
+	return yt.baseURL.ResolveReference(&url.URL{Path: path, RawQuery: params.Encode()}).String()
+}
+
+// get makes GET request and decodes JSON response.
+func (yt *YouTrack) get(u string, response interface{}) error {
+	return yt.do(httpwrap.Request{URL: u}, response)
+}
+
+// do makes request and decodes JSON response.
+func (yt *YouTrack) do(r httpwrap.Request, response interface{}) error {
+	body, err := yt.authorized(func(headers map[string]string) ([]byte, error) {
+		req := r
+		req.Headers = headers
+		resp, err := yt.requester.Do(&req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Body, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, response)
+}
+
+// authorized makes request with authorization header, headers are copied for each request to avoid shared state.
+// Request is retried once if authorization is rejected and may be renewed.
+func (yt *YouTrack) authorized(makeRequest func(headers map[string]string) ([]byte, error)) ([]byte, error) {
+	body, err := yt.request(makeRequest)
+	if statusErr, ok := err.(*httpwrap.StatusError); ok && statusErr.StatusCode == http.StatusUnauthorized && yt.authorizer.Invalidate() {
+		yt.log.Warn("authorization rejected, retrying with renewed token")
+		body, err = yt.request(makeRequest)
+	}
+	return body, err
+}
+
+func (yt *YouTrack) request(makeRequest func(headers map[string]string) ([]byte, error)) ([]byte, error) {
+	authorization, err := yt.authorizer.Authorization()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string, len(yt.headers)+1)
+	for key, val := range yt.headers {
+		headers[key] = val
+	}
+	headers["Authorization"] = authorization
+
+	return makeRequest(headers)
+}
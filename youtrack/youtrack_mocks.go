@@ -6,6 +6,8 @@ package youtrack
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	httpwrap "github.com/krpn/youtrack-issues-prometheus-exporter/httpwrap"
+	io "io"
 	reflect "reflect"
 )
 
@@ -32,15 +34,40 @@ func (m *MockmakeRequester) EXPECT() *MockmakeRequesterMockRecorder {
 	return m.recorder
 }
 
-// MakeRequest mocks base method
-func (m *MockmakeRequester) MakeRequest(url string, headers map[string]string) ([]byte, error) {
-	ret := m.ctrl.Call(m, "MakeRequest", url, headers)
-	ret0, _ := ret[0].([]byte)
+// Do mocks base method
+func (m *MockmakeRequester) Do(r *httpwrap.Request) (*httpwrap.Response, error) {
+	ret := m.ctrl.Call(m, "Do", r)
+	ret0, _ := ret[0].(*httpwrap.Response)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// MakeRequest indicates an expected call of MakeRequest
-func (mr *MockmakeRequesterMockRecorder) MakeRequest(url, headers interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeRequest", reflect.TypeOf((*MockmakeRequester)(nil).MakeRequest), url, headers)
+// Do indicates an expected call of Do
+func (mr *MockmakeRequesterMockRecorder) Do(r interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockmakeRequester)(nil).Do), r)
+}
+
+// StreamIfChanged mocks base method
+func (m *MockmakeRequester) StreamIfChanged(r *httpwrap.Request, hash string, read func(io.Reader) error) (string, error) {
+	ret := m.ctrl.Call(m, "StreamIfChanged", r, hash, read)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StreamIfChanged indicates an expected call of StreamIfChanged
+func (mr *MockmakeRequesterMockRecorder) StreamIfChanged(r, hash, read interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamIfChanged", reflect.TypeOf((*MockmakeRequester)(nil).StreamIfChanged), r, hash, read)
+}
+
+// Stream mocks base method
+func (m *MockmakeRequester) Stream(r *httpwrap.Request, read func(io.Reader) error) error {
+	ret := m.ctrl.Call(m, "Stream", r, read)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stream indicates an expected call of Stream
+func (mr *MockmakeRequesterMockRecorder) Stream(r, read interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockmakeRequester)(nil).Stream), r, read)
 }